@@ -0,0 +1,287 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package admin provides an [net/http.Handler] to view and change the log
+// levels and getters of a [slogctx.Handler] while a program is running.
+//
+//	levels := slogctx.NewLevels(slog.LevelInfo)
+//	ctrl := slogctx.NewController(getters...)
+//	h := slogctx.NewHandlerWithOptions(target, &slogctx.HandlerOptions{
+//		Levels: levels,
+//	}, ctrl)
+//
+//	mux.Handle("/debug/log/", http.StripPrefix("/debug/log", admin.NewHandler(levels, ctrl)))
+//
+// The handler serves the following, relative to where it is mounted:
+//
+//	GET    /                    the levels and getters
+//	GET    /levels              the base level and overrides
+//	PUT    /levels              set the base level, e.g. {"level":"DEBUG"}
+//	PUT    /levels/{component}  set the level for a component
+//	DELETE /levels/{component}  remove the level for a component
+//	GET    /getters             the getter keys and whether each is enabled
+//	PUT    /getters/{key}       enable or disable a key, e.g. {"enabled":false}
+//
+// Levels are formatted as by [log/slog.Level.MarshalJSON]. The handler does
+// not authenticate requests, so it should only be reachable by operators.
+package admin
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pfflabs/slogctx"
+)
+
+type (
+	handler struct {
+		levels *slogctx.Levels
+		ctrl   *slogctx.Controller
+	}
+
+	// State is the body of the response to GET /.
+	State struct {
+		Levels  *LevelsState  `json:"levels,omitempty"`
+		Getters []GetterState `json:"getters,omitempty"`
+	}
+
+	// LevelsState is the body of the response to GET /levels.
+	LevelsState struct {
+		Level     slog.Level            `json:"level"`
+		Overrides map[string]slog.Level `json:"overrides"`
+	}
+
+	// GetterState describes a single key in the response to GET /getters.
+	GetterState struct {
+		Key     string `json:"key"`
+		Enabled bool   `json:"enabled"`
+	}
+
+	levelRequest struct {
+		Level *slog.Level `json:"level"`
+	}
+
+	getterRequest struct {
+		Enabled *bool `json:"enabled"`
+	}
+)
+
+// NewHandler returns an [net/http.Handler] that changes the levels and
+// controller. Either may be nil, in which case its endpoints respond with
+// 404 Not Found.
+//
+// Panics if both levels and ctrl are nil.
+func NewHandler(levels *slogctx.Levels, ctrl *slogctx.Controller) http.Handler {
+	if levels == nil && ctrl == nil {
+		panic("levels and controller are nil")
+	}
+
+	return &handler{
+		levels: levels,
+		ctrl:   ctrl,
+	}
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(r.URL.Path, "/")
+	resource, name, _ := strings.Cut(path, "/")
+
+	switch {
+	case path == "":
+		h.serveState(w, r)
+
+	case resource == "levels" && h.levels != nil:
+		h.serveLevels(w, r, name)
+
+	case resource == "getters" && h.ctrl != nil:
+		h.serveGetters(w, r, name)
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *handler) serveState(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+
+	writeJSON(w, State{
+		Levels:  h.levelsState(),
+		Getters: h.gettersState(),
+	})
+}
+
+func (h *handler) serveLevels(
+	w http.ResponseWriter,
+	r *http.Request,
+	component string,
+) {
+	if len(component) == 0 {
+		if !allowMethods(w, r, http.MethodGet, http.MethodPut) {
+			return
+		}
+
+		if r.Method == http.MethodPut {
+			level, ok := readLevel(w, r)
+			if !ok {
+				return
+			}
+			h.levels.SetLevel(level)
+		}
+
+		writeJSON(w, h.levelsState())
+		return
+	}
+
+	if !allowMethods(w, r, http.MethodPut, http.MethodDelete) {
+		return
+	}
+
+	if r.Method == http.MethodPut {
+		level, ok := readLevel(w, r)
+		if !ok {
+			return
+		}
+		h.levels.SetOverride(component, level)
+	} else {
+		h.levels.RemoveOverride(component)
+	}
+
+	writeJSON(w, h.levelsState())
+}
+
+func (h *handler) serveGetters(
+	w http.ResponseWriter,
+	r *http.Request,
+	key string,
+) {
+	if len(key) == 0 {
+		if allowMethods(w, r, http.MethodGet) {
+			writeJSON(w, h.gettersState())
+		}
+		return
+	}
+
+	if !allowMethods(w, r, http.MethodPut) {
+		return
+	}
+
+	var req getterRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Enabled == nil {
+		http.Error(w, `missing "enabled"`, http.StatusBadRequest)
+		return
+	}
+
+	if *req.Enabled {
+		h.ctrl.Enable(key)
+	} else {
+		h.ctrl.Disable(key)
+	}
+
+	writeJSON(w, h.gettersState())
+}
+
+func (h *handler) levelsState() *LevelsState {
+	if h.levels == nil {
+		return nil
+	}
+
+	return &LevelsState{
+		Level:     h.levels.Level(),
+		Overrides: h.levels.Overrides(),
+	}
+}
+
+// gettersState returns the configured keys followed by any other disabled
+// keys, e.g. those of attributes from getters without a key.
+func (h *handler) gettersState() []GetterState {
+	if h.ctrl == nil {
+		return nil
+	}
+
+	var (
+		disabled = h.ctrl.Disabled()
+		keys     = h.ctrl.Keys()
+		state    = make([]GetterState, 0, len(keys)+len(disabled))
+	)
+	for _, k := range keys {
+		state = append(state, GetterState{
+			Key:     k,
+			Enabled: !slices.Contains(disabled, k),
+		})
+	}
+	for _, k := range disabled {
+		if !slices.Contains(keys, k) {
+			state = append(state, GetterState{Key: k})
+		}
+	}
+
+	return state
+}
+
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	if slices.Contains(methods, r.Method) {
+		return true
+	}
+
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	return false
+}
+
+func readLevel(w http.ResponseWriter, r *http.Request) (slog.Level, bool) {
+	var req levelRequest
+	if !readJSON(w, r, &req) {
+		return 0, false
+	}
+	if req.Level == nil {
+		http.Error(w, `missing "level"`, http.StatusBadRequest)
+		return 0, false
+	}
+
+	return *req.Level, true
+}
+
+// maxBodyBytes limits the size of request bodies, which are always small.
+const maxBodyBytes = 1 << 12
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		status := http.StatusBadRequest
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		http.Error(w, err.Error(), status)
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package admin_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/pfflabs/slogctx"
+	"github.com/pfflabs/slogctx/admin"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var fooGetter = slogctx.Attr("foo", func(context.Context) (int, bool) {
+	return 42, true
+})
+
+var barGetter = slogctx.Attr("bar", func(context.Context) (string, bool) {
+	return "oom", true
+})
+
+var _ = Describe("Administering a handler over HTTP", func() {
+	var (
+		levels  *slogctx.Levels
+		ctrl    *slogctx.Controller
+		handler http.Handler
+	)
+
+	BeforeEach(func() {
+		levels = slogctx.NewLevels(slog.LevelInfo)
+		ctrl = slogctx.NewController(fooGetter, barGetter)
+		handler = admin.NewHandler(levels, ctrl)
+	})
+
+	serve := func(method, path, body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return w
+	}
+
+	It("returns the current state", func() {
+		w := serve(http.MethodGet, "/", "")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(MatchJSON(`{
+			"levels": {"level": "INFO", "overrides": {}},
+			"getters": [
+				{"key": "foo", "enabled": true},
+				{"key": "bar", "enabled": true}
+			]
+		}`))
+	})
+
+	It("sets the base level", func() {
+		w := serve(http.MethodPut, "/levels", `{"level":"DEBUG"}`)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(levels.Level()).To(Equal(slog.LevelDebug))
+	})
+
+	It("sets and removes the level for a component", func() {
+		w := serve(http.MethodPut, "/levels/db", `{"level":"DEBUG"}`)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(MatchJSON(`{
+			"level": "INFO",
+			"overrides": {"db": "DEBUG"}
+		}`))
+		Expect(levels.LevelFor("db.pool")).To(Equal(slog.LevelDebug))
+
+		w = serve(http.MethodDelete, "/levels/db", "")
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(levels.LevelFor("db.pool")).To(Equal(slog.LevelInfo))
+	})
+
+	It("disables and enables a getter", func() {
+		w := serve(http.MethodPut, "/getters/foo", `{"enabled":false}`)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		Expect(w.Body.String()).To(MatchJSON(`[
+			{"key": "foo", "enabled": false},
+			{"key": "bar", "enabled": true}
+		]`))
+		Expect(ctrl.Disabled()).To(Equal([]string{"foo"}))
+
+		serve(http.MethodPut, "/getters/foo", `{"enabled":true}`)
+		Expect(ctrl.Disabled()).To(BeEmpty())
+	})
+
+	It("rejects an invalid level", func() {
+		w := serve(http.MethodPut, "/levels", `{"level":"LOUD"}`)
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(levels.Level()).To(Equal(slog.LevelInfo))
+	})
+
+	It("rejects a request without a value", func() {
+		w := serve(http.MethodPut, "/getters/foo", `{}`)
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("rejects an unsupported method", func() {
+		w := serve(http.MethodPost, "/levels", `{"level":"DEBUG"}`)
+		Expect(w.Code).To(Equal(http.StatusMethodNotAllowed))
+		Expect(w.Header().Get("Allow")).To(Equal("GET, PUT"))
+	})
+
+	It("responds with not found for an unknown path", func() {
+		Expect(serve(http.MethodGet, "/unknown", "").Code).
+			To(Equal(http.StatusNotFound))
+	})
+
+	When("there is no controller", func() {
+
+		BeforeEach(func() {
+			handler = admin.NewHandler(levels, nil)
+		})
+
+		It("responds with not found for the getters", func() {
+			Expect(serve(http.MethodGet, "/getters", "").Code).
+				To(Equal(http.StatusNotFound))
+		})
+	})
+
+	When("there are no levels or controller", func() {
+
+		It("panics", func() {
+			Expect(func() { admin.NewHandler(nil, nil) }).
+				To(PanicWith("levels and controller are nil"))
+		})
+	})
+})
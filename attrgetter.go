@@ -28,6 +28,20 @@ type (
 
 	// AttrGetterFunc is a [AttrGetter] implemented as a single function.
 	AttrGetterFunc func(context.Context) []slog.Attr
+
+	// AttrAppender is an optional interface implemented by an [AttrGetter]
+	// that can append its attributes to an existing slice instead of
+	// allocating a new one. The getters returned by [Attr] and [Group]
+	// implement it.
+	AttrAppender interface {
+		AppendAttrs(ctx context.Context, dst []slog.Attr) []slog.Attr
+	}
+
+	// keyedAttrGetter is implemented by getters that return, at most, a
+	// single attribute with a key known in advance.
+	keyedAttrGetter interface {
+		attrKey() string
+	}
 )
 
 // GetAttrs returns the [log/slog.Attr] instances from the backing
@@ -35,3 +49,12 @@ type (
 func (f AttrGetterFunc) GetAttrs(ctx context.Context) []slog.Attr {
 	return f(ctx)
 }
+
+// appendAttrs appends the attributes from g to dst, avoiding an allocation
+// when g is an [AttrAppender].
+func appendAttrs(ctx context.Context, g AttrGetter, dst []slog.Attr) []slog.Attr {
+	if a, ok := g.(AttrAppender); ok {
+		return a.AppendAttrs(ctx, dst)
+	}
+	return append(dst, g.GetAttrs(ctx)...)
+}
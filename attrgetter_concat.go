@@ -18,18 +18,26 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"reflect"
+	"slices"
 	"strings"
 )
 
 type concatAttrGetter []AttrGetter
 
 func (c *concatAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
-	attrs := make([]slog.Attr, 0, len(*c))
+	return c.AppendAttrs(ctx, make([]slog.Attr, 0, len(*c)))
+}
+
+func (c *concatAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
 	for _, g := range *c {
-		attrs = append(attrs, g.GetAttrs(ctx)...)
+		dst = appendAttrs(ctx, g, dst)
 	}
 
-	return attrs
+	return dst
 }
 
 func concat(gs []AttrGetter) AttrGetter {
@@ -53,6 +61,21 @@ func concat(gs []AttrGetter) AttrGetter {
 	return &c
 }
 
+// splitConcat returns the getters concatenated by g, or g itself.
+func splitConcat(g AttrGetter) []AttrGetter {
+	if c, ok := g.(*concatAttrGetter); ok {
+		return slices.Clone(*c)
+	}
+	return []AttrGetter{g}
+}
+
+// sameAttrGetter returns whether a and b are the same getter. Getters of
+// types that cannot be compared, such as AttrGetterFunc, are never the same.
+func sameAttrGetter(a, b AttrGetter) bool {
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
 func validateAttrGetters(gs []AttrGetter) {
 	var (
 		n       = len(gs)
@@ -17,6 +17,7 @@ package slogctx
 import (
 	"context"
 	"log/slog"
+	"slices"
 )
 
 type groupAttrGetter struct {
@@ -24,6 +25,10 @@ type groupAttrGetter struct {
 	AttrGetter
 }
 
+func (g *groupAttrGetter) attrKey() string {
+	return g.key
+}
+
 func (g *groupAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
 	attrs := g.AttrGetter.GetAttrs(ctx)
 	if len(attrs) == 0 {
@@ -35,6 +40,22 @@ func (g *groupAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
 	}
 }
 
+func (g *groupAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	n := len(dst)
+	dst = appendAttrs(ctx, g.AttrGetter, dst)
+	if len(dst) == n {
+		return dst
+	}
+
+	// The group's value must not share memory with dst, which the caller may
+	// reuse once the attributes have been added to a record.
+	attrs := slices.Clone(dst[n:])
+	return append(dst[:n], slog.Attr{Key: g.key, Value: slog.GroupValue(attrs...)})
+}
+
 // Group returns a [AttrGetter] that groups one or more [AttrGetter]
 // instances.
 //
@@ -42,6 +42,29 @@ var _ = Describe("Grouping AttrGetter instances", func() {
 		})
 	})
 
+	When("appending the group attributes to a slice", func() {
+		getter := slogctx.Group(groupName, fooGetter, barGetter)
+		ctx := context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+		dst := make([]slog.Attr, 1, 8)
+		dst[0] = pifAttr
+		ret := getter.(slogctx.AttrAppender).AppendAttrs(ctx, dst)
+
+		It("appends the group of attributes", func() {
+			Expect(ret).To(Equal([]slog.Attr{
+				pifAttr,
+				slog.Group(groupName, fooAttr, barAttr),
+			}))
+		})
+
+		It("does not share memory between the group and the slice", func() {
+			buf := getter.(slogctx.AttrAppender).AppendAttrs(ctx, nil)
+			group := buf[0]
+			clear(buf[:cap(buf)])
+			Expect(group).To(Equal(slog.Group(groupName, fooAttr, barAttr)))
+		})
+	})
+
 	When("zero group attributes are found in the context", func() {
 		getter := slogctx.Group(groupName, fooGetter, barGetter)
 		ret := getter.GetAttrs(context.Background())
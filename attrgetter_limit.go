@@ -0,0 +1,229 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultTruncatedKey is the key of the attribute listing the truncated
+// attributes if [LimitOptions.TruncatedKey] is empty.
+const DefaultTruncatedKey = "truncated"
+
+// LimitOptions are the limits used by [Limit]. A zero value for a limit
+// means there is no limit.
+type LimitOptions struct {
+	// MaxValueLen is the maximum length, in bytes, of a single value.
+	MaxValueLen int
+
+	// MaxTotalLen is the maximum length, in bytes, of all keys and values.
+	MaxTotalLen int
+
+	// TruncatedKey is the key of an attribute listing the keys of truncated
+	// or omitted attributes, e.g. ["headers.cookie"]. If empty,
+	// [DefaultTruncatedKey] is used.
+	TruncatedKey string
+}
+
+type limitAttrGetter struct {
+	LimitOptions
+	AttrGetter
+}
+
+// Limit returns an [AttrGetter] that limits the size of the attributes
+// returned by one or more [AttrGetter] instances.
+//
+// A string value longer than the limit is cut, on a UTF-8 boundary, and
+// marked with the number of bytes removed, e.g. "abc…[truncated 4096 bytes]".
+// Other values are formatted as by [log/slog.Value.String] to check their
+// length, and values created by [log/slog.Any] are replaced by their
+// formatted and cut string if they are too long. Other values that do not
+// fit are omitted, as are attributes once the total is used up. Markers,
+// and the attribute reporting which attributes were limited, do not count
+// towards the limits.
+//
+// Panics if a limit is negative, receives zero [AttrGetter] instances, or
+// any [AttrGetter] references are nil.
+func Limit(opts LimitOptions, attrGetters ...AttrGetter) AttrGetter {
+	if opts.MaxValueLen < 0 || opts.MaxTotalLen < 0 {
+		panic("limit is negative")
+	}
+	if len(opts.TruncatedKey) == 0 {
+		opts.TruncatedKey = DefaultTruncatedKey
+	}
+
+	return &limitAttrGetter{
+		LimitOptions: opts,
+		AttrGetter:   concat(attrGetters),
+	}
+}
+
+func (l *limitAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return l.AppendAttrs(ctx, nil)
+}
+
+func (l *limitAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	n := len(dst)
+	dst = appendAttrs(ctx, l.AttrGetter, dst)
+	if l.MaxValueLen == 0 && l.MaxTotalLen == 0 {
+		return dst
+	}
+
+	lim := limiter{LimitOptions: &l.LimitOptions, remaining: l.MaxTotalLen}
+	kept := lim.limitAttrs(dst[n:], "", false)
+	dst = dst[:n+len(kept)]
+
+	if len(lim.truncated) > 0 {
+		dst = append(dst, slog.Any(l.TruncatedKey, lim.truncated))
+	}
+	return dst
+}
+
+type limiter struct {
+	*LimitOptions
+	remaining int
+	truncated []string
+}
+
+// limitAttrs limits attrs, returning the attributes that are kept. If
+// shared is true the attributes are copied rather than modified.
+func (l *limiter) limitAttrs(attrs []slog.Attr, prefix string, shared bool) []slog.Attr {
+	kept := attrs[:0]
+	if shared {
+		kept = make([]slog.Attr, 0, len(attrs))
+	}
+
+	for _, a := range attrs {
+		if a, ok := l.limitAttr(a, prefix); ok {
+			kept = append(kept, a)
+		}
+	}
+
+	return kept
+}
+
+func (l *limiter) limitAttr(a slog.Attr, prefix string) (slog.Attr, bool) {
+	path := a.Key
+	if len(prefix) > 0 {
+		path = prefix + "." + a.Key
+	}
+
+	if l.MaxTotalLen > 0 {
+		if l.remaining <= len(a.Key) {
+			l.truncated = append(l.truncated, path)
+			return a, false
+		}
+		l.remaining -= len(a.Key)
+	}
+
+	a.Value = a.Value.Resolve()
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		// The group's attributes may be shared with the getter.
+		attrs := l.limitAttrs(a.Value.Group(), path, true)
+		if len(attrs) == 0 {
+			return a, false
+		}
+		a.Value = slog.GroupValue(attrs...)
+		return a, true
+
+	case slog.KindString:
+		s, ok := l.limitString(a.Value.String(), path)
+		a.Value = slog.StringValue(s)
+		return a, ok
+
+	case slog.KindAny:
+		s := a.Value.String()
+		if l.fits(len(s)) {
+			l.use(len(s))
+			return a, true
+		}
+		s, ok := l.limitString(s, path)
+		a.Value = slog.StringValue(s)
+		return a, ok
+	}
+
+	if size := scalarLen(a.Value); l.fits(size) {
+		l.use(size)
+		return a, true
+	}
+	l.truncated = append(l.truncated, path)
+	return a, false
+}
+
+// limitString cuts s if it does not fit, returning false if nothing fits.
+func (l *limiter) limitString(s, path string) (string, bool) {
+	if l.fits(len(s)) {
+		l.use(len(s))
+		return s, true
+	}
+
+	end := len(s)
+	if l.MaxValueLen > 0 {
+		end = min(end, l.MaxValueLen)
+	}
+	if l.MaxTotalLen > 0 {
+		end = min(end, l.remaining)
+	}
+
+	// Back up to the start of a rune.
+	for end > 0 && !utf8.RuneStart(s[end]) {
+		end--
+	}
+
+	l.truncated = append(l.truncated, path)
+	if end == 0 {
+		return "", false
+	}
+
+	l.use(end)
+	return s[:end] + "\u2026[truncated " + strconv.Itoa(len(s)-end) + " bytes]", true
+}
+
+func (l *limiter) fits(size int) bool {
+	return (l.MaxValueLen == 0 || size <= l.MaxValueLen) &&
+		(l.MaxTotalLen == 0 || size <= l.remaining)
+}
+
+func (l *limiter) use(size int) {
+	l.remaining -= size
+}
+
+// scalarLen returns the length of v, which is not a string, group or any,
+// when formatted.
+func scalarLen(v slog.Value) int {
+	var buf [64]byte
+	switch v.Kind() {
+	case slog.KindBool:
+		return len(strconv.AppendBool(buf[:0], v.Bool()))
+	case slog.KindInt64:
+		return len(strconv.AppendInt(buf[:0], v.Int64(), 10))
+	case slog.KindUint64:
+		return len(strconv.AppendUint(buf[:0], v.Uint64(), 10))
+	case slog.KindFloat64:
+		return len(strconv.AppendFloat(buf[:0], v.Float64(), 'g', -1, 64))
+	case slog.KindTime:
+		return len(v.Time().AppendFormat(buf[:0], time.RFC3339Nano))
+	}
+
+	return len(v.String())
+}
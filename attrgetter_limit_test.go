@@ -0,0 +1,100 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Limiting the size of attributes", func() {
+	ctx := context.Background()
+
+	limit := func(opts slogctx.LimitOptions, attrs ...slog.Attr) []slog.Attr {
+		getter := slogctx.AttrGetterFunc(func(context.Context) []slog.Attr {
+			return attrs
+		})
+		return slogctx.Limit(opts, getter).GetAttrs(ctx)
+	}
+
+	When("the attributes are within the limits", func() {
+		ret := limit(slogctx.LimitOptions{MaxValueLen: 4, MaxTotalLen: 100},
+			fooAttr, barAttr, pifAttr)
+
+		It("returns the attributes unchanged", func() {
+			Expect(ret).To(Equal([]slog.Attr{fooAttr, barAttr, pifAttr}))
+		})
+	})
+
+	When("a value is longer than the maximum length", func() {
+		ret := limit(slogctx.LimitOptions{MaxValueLen: 4},
+			slog.String("token", strings.Repeat("x", 10)), fooAttr)
+
+		It("cuts and marks the value and reports the key", func() {
+			Expect(ret).To(Equal([]slog.Attr{
+				slog.String("token", "xxxx…[truncated 6 bytes]"),
+				fooAttr,
+				slog.Any(slogctx.DefaultTruncatedKey, []string{"token"}),
+			}))
+		})
+	})
+
+	When("a value would be cut within a multi-byte character", func() {
+		ret := limit(slogctx.LimitOptions{MaxValueLen: 4}, slog.String("s", "ab世界"))
+
+		It("cuts the value before the character", func() {
+			Expect(ret[0]).To(Equal(slog.String("s", "ab…[truncated 6 bytes]")))
+		})
+	})
+
+	When("an any value is longer than the maximum length", func() {
+		ret := limit(slogctx.LimitOptions{MaxValueLen: 3},
+			slog.Any("claims", map[string]int{"a": 1, "b": 2}))
+
+		It("replaces the value with its cut string", func() {
+			Expect(ret[0]).To(Equal(slog.String("claims", "map…[truncated 9 bytes]")))
+		})
+	})
+
+	When("the attributes exceed the total length", func() {
+		ret := limit(slogctx.LimitOptions{MaxTotalLen: 12, TruncatedKey: "cut"},
+			slog.String("a", "1234"),
+			slog.Group("g", slog.String("b", "123456789"), slog.Int("c", 1)),
+			slog.String("d", "1"),
+		)
+
+		It("cuts the value that reaches the limit and omits the rest", func() {
+			Expect(ret).To(Equal([]slog.Attr{
+				slog.String("a", "1234"),
+				slog.Group("g", slog.String("b", "12345…[truncated 4 bytes]")),
+				slog.Any("cut", []string{"g.b", "g.c", "d"}),
+			}))
+		})
+	})
+
+	When("a limit is negative", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Limit(slogctx.LimitOptions{MaxValueLen: -1}, fooGetter) }).
+				To(PanicWith("limit is negative"))
+		})
+	})
+})
@@ -0,0 +1,212 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// IgnoreZero returns a function suitable for [Attr] that will return a
+// false status if the value returned by lookup is the type's 'zero' value.
+func IgnoreZero[T any](
+	lookup func(context.Context) T,
+) func(context.Context) (value T, ok bool) {
+	return OmitIf(lookup, isZeroFunc[T]())
+}
+
+// LogZero returns a function suitable for [Attr] that will always return
+// a true status. Useful if the type's 'zero' value should be included in
+// logged output.
+func LogZero[T any](
+	lookup func(context.Context) T,
+) func(context.Context) (value T, ok bool) {
+	if lookup == nil {
+		panic("lookup is nil")
+	}
+
+	return func(ctx context.Context) (T, bool) {
+		return lookup(ctx), true
+	}
+}
+
+// IgnoreEmpty returns a function suitable for [Attr] that will return a
+// false status if the value returned by lookup is empty. Strings are empty
+// if they only contain white space, slices and maps are empty if they have
+// zero elements, and other types are empty if they are the type's 'zero'
+// value.
+func IgnoreEmpty[T any](
+	lookup func(context.Context) T,
+) func(context.Context) (value T, ok bool) {
+	return OmitIf(lookup, isEmptyFunc[T]())
+}
+
+// IgnoreNil returns a function suitable for [Attr] that will return a false
+// status if the value returned by lookup is nil. An interface holding a nil
+// pointer, map, slice, etc. is also considered nil.
+//
+// Panics if lookup is nil or T is a type that cannot be nil.
+func IgnoreNil[T any](
+	lookup func(context.Context) T,
+) func(context.Context) (value T, ok bool) {
+	return OmitIf(lookup, isNilFunc[T]())
+}
+
+// OmitIf returns a function suitable for [Attr] that will return a false
+// status if pred returns true for the value returned by lookup.
+//
+// Panics if lookup or pred is nil.
+func OmitIf[T any](
+	lookup func(context.Context) T,
+	pred func(T) bool,
+) func(context.Context) (value T, ok bool) {
+	if lookup == nil {
+		panic("lookup is nil")
+	}
+	if pred == nil {
+		panic("pred is nil")
+	}
+
+	return func(ctx context.Context) (T, bool) {
+		t := lookup(ctx)
+		return t, !pred(t)
+	}
+}
+
+// Default returns a function suitable for [Attr] that will return fallback,
+// and a true status, whenever lookup returns a false status. Useful to log
+// a placeholder such as "unknown" when a value is missing.
+//
+//	slogctx.Attr("tenant", slogctx.Default(tenantpkg.FromCtx, "unknown"))
+//
+// Panics if lookup is nil.
+func Default[T any](
+	lookup func(context.Context) (T, bool),
+	fallback T,
+) func(context.Context) (value T, ok bool) {
+	if lookup == nil {
+		panic("lookup is nil")
+	}
+
+	return func(ctx context.Context) (T, bool) {
+		if t, ok := lookup(ctx); ok {
+			return t, true
+		}
+		return fallback, true
+	}
+}
+
+// isZeroFunc returns a function reporting whether a T is the type's 'zero'
+// value. Types that can be compared using == avoid using reflect.
+func isZeroFunc[T any]() func(T) bool {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	switch typ.Kind() {
+	case reflect.Interface:
+		return func(t T) bool {
+			v := reflect.ValueOf(any(t))
+			return !v.IsValid() || v.IsZero()
+		}
+
+	case reflect.Bool, reflect.String, reflect.Pointer, reflect.Chan,
+		reflect.UnsafePointer, reflect.Complex64, reflect.Complex128,
+		reflect.Float32, reflect.Float64, reflect.Int, reflect.Int8,
+		reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Uintptr:
+		zero := any(*new(T))
+		return func(t T) bool {
+			return any(t) == zero
+		}
+	}
+
+	return func(t T) bool {
+		return reflect.ValueOf(t).IsZero()
+	}
+}
+
+// isEmptyFunc returns a function reporting whether a T is empty as
+// described by [IgnoreEmpty].
+func isEmptyFunc[T any]() func(T) bool {
+	if f, ok := any(isBlank).(func(T) bool); ok {
+		return f
+	}
+
+	switch reflect.TypeOf((*T)(nil)).Elem().Kind() {
+	case reflect.Interface, reflect.String, reflect.Map, reflect.Slice:
+		return func(t T) bool {
+			return isEmptyValue(reflect.ValueOf(any(t)))
+		}
+	}
+
+	return isZeroFunc[T]()
+}
+
+func isBlank(s string) bool {
+	return len(strings.TrimSpace(s)) == 0
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return isBlank(v.String())
+
+	case reflect.Map, reflect.Slice:
+		return v.Len() == 0
+	}
+
+	return v.IsZero()
+}
+
+// isNilFunc returns a function reporting whether a T is nil as described by
+// [IgnoreNil].
+func isNilFunc[T any]() func(T) bool {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	switch typ.Kind() {
+	case reflect.Interface:
+		return func(t T) bool {
+			return isNilValue(reflect.ValueOf(any(t)))
+		}
+
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return isZeroFunc[T]()
+
+	case reflect.Func, reflect.Map, reflect.Slice:
+		return func(t T) bool {
+			return reflect.ValueOf(t).IsNil()
+		}
+	}
+
+	panic(fmt.Sprintf("%s cannot be nil", typ))
+}
+
+func isNilValue(v reflect.Value) bool {
+	if !v.IsValid() {
+		return true
+	}
+
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map,
+		reflect.Pointer, reflect.Slice, reflect.UnsafePointer:
+		return v.IsNil()
+	}
+
+	return false
+}
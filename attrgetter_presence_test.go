@@ -0,0 +1,139 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func returns[T any](t T) func(context.Context) T {
+	return func(context.Context) T { return t }
+}
+
+var _ = Describe("Deciding whether a looked up value is present", func() {
+	ctx := context.Background()
+
+	DescribeTable("IgnoreZero",
+		func(lookup func(context.Context) (any, bool), expected bool) {
+			_, ok := lookup(ctx)
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("zero int", wrap(slogctx.IgnoreZero(returns(0))), false),
+		Entry("non-zero int", wrap(slogctx.IgnoreZero(returns(1))), true),
+		Entry("empty string", wrap(slogctx.IgnoreZero(returns(""))), false),
+		Entry("zero time", wrap(slogctx.IgnoreZero(returns(time.Time{}))), false),
+		Entry("nil interface", wrap(slogctx.IgnoreZero(returns[any](nil))), false),
+		Entry("interface holding zero", wrap(slogctx.IgnoreZero(returns[any](0))), false),
+		Entry("nil slice", wrap(slogctx.IgnoreZero(returns[[]int](nil))), false),
+		Entry("empty slice", wrap(slogctx.IgnoreZero(returns([]int{}))), true),
+	)
+
+	DescribeTable("IgnoreEmpty",
+		func(lookup func(context.Context) (any, bool), expected bool) {
+			_, ok := lookup(ctx)
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("empty string", wrap(slogctx.IgnoreEmpty(returns(""))), false),
+		Entry("blank string", wrap(slogctx.IgnoreEmpty(returns(" \t\n"))), false),
+		Entry("string", wrap(slogctx.IgnoreEmpty(returns(" a "))), true),
+		Entry("blank named string", wrap(slogctx.IgnoreEmpty(returns(ctxKey(" ")))), false),
+		Entry("empty slice", wrap(slogctx.IgnoreEmpty(returns([]int{}))), false),
+		Entry("slice", wrap(slogctx.IgnoreEmpty(returns([]int{1}))), true),
+		Entry("empty map", wrap(slogctx.IgnoreEmpty(returns(map[string]int{}))), false),
+		Entry("interface holding blank string", wrap(slogctx.IgnoreEmpty(returns[any](" "))), false),
+		Entry("zero int", wrap(slogctx.IgnoreEmpty(returns(0))), false),
+		Entry("non-zero int", wrap(slogctx.IgnoreEmpty(returns(1))), true),
+	)
+
+	DescribeTable("IgnoreNil",
+		func(lookup func(context.Context) (any, bool), expected bool) {
+			_, ok := lookup(ctx)
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("nil pointer", wrap(slogctx.IgnoreNil(returns[*int](nil))), false),
+		Entry("pointer", wrap(slogctx.IgnoreNil(returns(new(int)))), true),
+		Entry("nil error", wrap(slogctx.IgnoreNil(returns[error](nil))), false),
+		Entry("error", wrap(slogctx.IgnoreNil(returns(errors.New("x")))), true),
+		Entry("interface holding nil pointer", wrap(slogctx.IgnoreNil(returns[any]((*int)(nil)))), false),
+		Entry("nil map", wrap(slogctx.IgnoreNil(returns[map[string]int](nil))), false),
+		Entry("empty map", wrap(slogctx.IgnoreNil(returns(map[string]int{}))), true),
+	)
+
+	When("using OmitIf", func() {
+		lookup := slogctx.OmitIf(returns(-1), func(v int) bool { return v < 0 })
+
+		It("returns a false status if the predicate is true", func() {
+			_, ok := lookup(ctx)
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	When("using Default", func() {
+		lookup := slogctx.Default(slogctx.IgnoreZero(returns("")), "unknown")
+
+		It("returns the fallback value if the value is not found", func() {
+			v, ok := lookup(ctx)
+			Expect(ok).To(BeTrue())
+			Expect(v).To(Equal("unknown"))
+		})
+
+		It("is recognized by Attr", func() {
+			Expect(slogctx.Attr(fooAttrName, lookup).GetAttrs(ctx)).To(
+				ContainElement(slog.String(fooAttrName, "unknown")),
+			)
+		})
+	})
+})
+
+var _ = When("passing nil to OmitIf", func() {
+
+	It("panics", func() {
+		Expect(func() { slogctx.OmitIf[any](nil, func(any) bool { return true }) }).
+			To(PanicWith("lookup is nil"))
+		Expect(func() { slogctx.OmitIf(returns(0), nil) }).
+			To(PanicWith("pred is nil"))
+	})
+})
+
+var _ = When("passing nil to Default", func() {
+
+	It("panics", func() {
+		Expect(func() { slogctx.Default(nil, 0) }).To(PanicWith("lookup is nil"))
+	})
+})
+
+var _ = When("passing a type that cannot be nil to IgnoreNil", func() {
+
+	It("panics", func() {
+		Expect(func() { slogctx.IgnoreNil(returns(0)) }).
+			To(PanicWith("int cannot be nil"))
+	})
+})
+
+func wrap[T any](
+	lookup func(context.Context) (T, bool),
+) func(context.Context) (any, bool) {
+	return func(ctx context.Context) (any, bool) {
+		return lookup(ctx)
+	}
+}
@@ -0,0 +1,310 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// ProtoRedacted is the value logged in place of fields marked with the
+// debug_redact option when [ProtoRedact] is used.
+const ProtoRedacted = "[REDACTED]"
+
+type (
+	// ProtoOption configures how [Proto] converts a message into attributes.
+	ProtoOption func(*protoConfig)
+
+	protoConfig struct {
+		mask      protoMask
+		redact    bool
+		jsonNames bool
+	}
+
+	// protoMask is a tree of field names. A nil mask selects every field,
+	// as does an empty mask below the root.
+	protoMask map[protoreflect.Name]protoMask
+
+	protoAttrGetter[M proto.Message] struct {
+		key    string
+		lookup func(context.Context) (M, bool)
+		protoConfig
+	}
+)
+
+// ProtoFieldMask limits the logged fields to those named by paths. Paths use
+// the original (not JSON) field names and nested fields are separated by a
+// '.', e.g. "config.hostname". Paths naming unknown fields match nothing.
+//
+// Panics if a path, or any of its elements, is empty.
+func ProtoFieldMask(paths ...string) ProtoOption {
+	mask := protoMask{}
+	for _, path := range paths {
+		mask.add(path)
+	}
+
+	return func(c *protoConfig) {
+		if len(mask) > 0 {
+			c.mask = mask
+		}
+	}
+}
+
+// ProtoRedact logs [ProtoRedacted] in place of the value of any field that
+// has the debug_redact option set.
+func ProtoRedact() ProtoOption {
+	return func(c *protoConfig) {
+		c.redact = true
+	}
+}
+
+// ProtoJSONNames uses the fields' JSON names as attribute keys instead of
+// the names used in the message definition.
+func ProtoJSONNames() ProtoOption {
+	return func(c *protoConfig) {
+		c.jsonNames = true
+	}
+}
+
+// Proto returns an [AttrGetter] that logs a protobuf message taken from a
+// [context.Context] as a group of attributes, one for each populated field.
+//
+// Nested messages become nested groups, enums are logged by name, bytes are
+// base64 encoded, and google.protobuf.Timestamp and google.protobuf.Duration
+// messages are logged as time values. Repeated and map fields are logged as
+// a []any or map[string]any respectively.
+//
+// Panics if key is empty or lookup is nil.
+func Proto[M proto.Message](
+	key string,
+	lookup func(context.Context) (value M, ok bool),
+	opts ...ProtoOption,
+) AttrGetter {
+	validateKey(key)
+	if lookup == nil {
+		panic("lookup is nil")
+	}
+
+	g := &protoAttrGetter[M]{
+		key:    key,
+		lookup: lookup,
+	}
+	for _, opt := range opts {
+		opt(&g.protoConfig)
+	}
+
+	return g
+}
+
+func (g *protoAttrGetter[M]) attrKey() string {
+	return g.key
+}
+
+func (g *protoAttrGetter[M]) GetAttrs(ctx context.Context) []slog.Attr {
+	m, ok := g.lookup(ctx)
+	if !ok || any(m) == nil {
+		return nil
+	}
+
+	msg := m.ProtoReflect()
+	if !msg.IsValid() {
+		return nil
+	}
+
+	v := g.messageValue(msg, g.mask)
+	if v.Kind() == slog.KindGroup && len(v.Group()) == 0 {
+		return nil
+	}
+
+	return []slog.Attr{{Key: g.key, Value: v}}
+}
+
+func (c *protoConfig) messageValue(
+	msg protoreflect.Message,
+	mask protoMask,
+) slog.Value {
+	switch msg.Descriptor().FullName() {
+	case "google.protobuf.Timestamp":
+		s, ns := protoSecondsNanos(msg)
+		return slog.TimeValue(time.Unix(s, ns).UTC())
+
+	case "google.protobuf.Duration":
+		s, ns := protoSecondsNanos(msg)
+		return slog.DurationValue(time.Duration(s)*time.Second + time.Duration(ns))
+	}
+
+	fields := msg.Descriptor().Fields()
+	attrs := make([]slog.Attr, 0, fields.Len())
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		sub, ok := mask.lookup(fd.Name())
+		if !ok || !msg.Has(fd) {
+			continue
+		}
+
+		attrs = append(attrs, slog.Attr{
+			Key:   c.fieldName(fd),
+			Value: c.fieldValue(fd, msg.Get(fd), sub),
+		})
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+func (c *protoConfig) fieldName(fd protoreflect.FieldDescriptor) string {
+	if c.jsonNames {
+		return fd.JSONName()
+	}
+	return string(fd.Name())
+}
+
+func (c *protoConfig) fieldValue(
+	fd protoreflect.FieldDescriptor,
+	v protoreflect.Value,
+	mask protoMask,
+) slog.Value {
+	if c.redact && isDebugRedact(fd) {
+		return slog.StringValue(ProtoRedacted)
+	}
+
+	switch {
+	case fd.IsMap():
+		m := make(map[string]any, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, v protoreflect.Value) bool {
+			m[k.String()] = valueToAny(c.singularValue(fd.MapValue(), v, mask))
+			return true
+		})
+		return slog.AnyValue(m)
+
+	case fd.IsList():
+		l := make([]any, v.List().Len())
+		for i := range l {
+			l[i] = valueToAny(c.singularValue(fd, v.List().Get(i), mask))
+		}
+		return slog.AnyValue(l)
+	}
+
+	return c.singularValue(fd, v, mask)
+}
+
+func (c *protoConfig) singularValue(
+	fd protoreflect.FieldDescriptor,
+	v protoreflect.Value,
+	mask protoMask,
+) slog.Value {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return slog.BoolValue(v.Bool())
+
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return slog.StringValue(string(ev.Name()))
+		}
+		return slog.Int64Value(int64(v.Enum()))
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind,
+		protoreflect.Sfixed32Kind, protoreflect.Int64Kind,
+		protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return slog.Int64Value(v.Int())
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return slog.Uint64Value(v.Uint())
+
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return slog.Float64Value(v.Float())
+
+	case protoreflect.StringKind:
+		return slog.StringValue(v.String())
+
+	case protoreflect.BytesKind:
+		return slog.StringValue(base64.StdEncoding.EncodeToString(v.Bytes()))
+
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return c.messageValue(v.Message(), mask)
+	}
+
+	return slog.AnyValue(v.Interface())
+}
+
+func (m protoMask) add(path string) {
+	for _, name := range strings.Split(path, ".") {
+		if len(name) == 0 {
+			panic(fmt.Sprintf("invalid field mask path %q", path))
+		}
+
+		sub, ok := m[protoreflect.Name(name)]
+		if ok && len(sub) == 0 {
+			// An ancestor path already selects everything below it.
+			return
+		}
+		if !ok {
+			sub = protoMask{}
+			m[protoreflect.Name(name)] = sub
+		}
+		m = sub
+	}
+
+	// Clearing the leaf selects every field below it.
+	for k := range m {
+		delete(m, k)
+	}
+}
+
+func (m protoMask) lookup(name protoreflect.Name) (protoMask, bool) {
+	if m == nil {
+		return nil, true
+	}
+
+	sub, ok := m[name]
+	if ok && len(sub) == 0 {
+		return nil, true
+	}
+	return sub, ok
+}
+
+func isDebugRedact(fd protoreflect.FieldDescriptor) bool {
+	opts, ok := fd.Options().(*descriptorpb.FieldOptions)
+	return ok && opts.GetDebugRedact()
+}
+
+func protoSecondsNanos(msg protoreflect.Message) (int64, int64) {
+	fields := msg.Descriptor().Fields()
+	s := msg.Get(fields.ByName("seconds")).Int()
+	ns := msg.Get(fields.ByName("nanos")).Int()
+	return s, ns
+}
+
+// valueToAny converts v into a value suitable for use inside a []any or
+// map[string]any, which cannot hold groups.
+func valueToAny(v slog.Value) any {
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+
+	m := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		m[a.Key] = valueToAny(a.Value)
+	}
+	return m
+}
@@ -0,0 +1,271 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Creating an AttrGetter for a protobuf message", func() {
+	field := &descriptorpb.FieldDescriptorProto{
+		Name:     proto.String("id"),
+		Number:   proto.Int32(3),
+		Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		TypeName: proto.String(".foo.Bar"),
+		Options:  &descriptorpb.FieldOptions{Deprecated: proto.Bool(true)},
+	}
+	lookup := func(ctx context.Context) (*descriptorpb.FieldDescriptorProto, bool) {
+		v, ok := ctx.Value(fooCtxKey).(*descriptorpb.FieldDescriptorProto)
+		return v, ok
+	}
+	ctx := context.WithValue(context.Background(), fooCtxKey, field)
+
+	When("the key is an empty string", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Proto("", lookup) }).To(
+				PanicWith("key is empty"),
+			)
+		})
+	})
+
+	When("the lookup function is nil", func() {
+
+		It("panics", func() {
+			Expect(func() {
+				slogctx.Proto[*descriptorpb.FieldDescriptorProto](fooAttrName, nil)
+			}).To(
+				PanicWith("lookup is nil"),
+			)
+		})
+	})
+
+	When("the message is not found in the context", func() {
+		ret := slogctx.Proto(fooAttrName, lookup).GetAttrs(context.Background())
+
+		It("returns an empty slice of attributes", func() {
+			Expect(ret).To(BeEmpty())
+		})
+	})
+
+	When("the message is found in the context", func() {
+		ret := slogctx.Proto(fooAttrName, lookup).GetAttrs(ctx)
+
+		It("returns a group containing the populated fields", func() {
+			Expect(ret).To(
+				And(
+					HaveLen(1),
+					ContainElement(slog.Group(fooAttrName,
+						slog.String("name", "id"),
+						slog.Int64("number", 3),
+						slog.String("label", "LABEL_OPTIONAL"),
+						slog.String("type", "TYPE_STRING"),
+						slog.String("type_name", ".foo.Bar"),
+						slog.Group("options", slog.Bool("deprecated", true)),
+					)),
+				),
+			)
+		})
+	})
+
+	When("using JSON field names", func() {
+		getter := slogctx.Proto(fooAttrName, lookup,
+			slogctx.ProtoJSONNames(),
+			slogctx.ProtoFieldMask("type_name"),
+		)
+		ret := getter.GetAttrs(ctx)
+
+		It("uses the JSON name as the attribute key", func() {
+			Expect(ret).To(ContainElement(
+				slog.Group(fooAttrName, slog.String("typeName", ".foo.Bar")),
+			))
+		})
+	})
+
+	When("using a field mask", func() {
+		getter := slogctx.Proto(fooAttrName, lookup,
+			slogctx.ProtoFieldMask("name", "options.deprecated", "json_name"),
+		)
+		ret := getter.GetAttrs(ctx)
+
+		It("returns only the selected, populated fields", func() {
+			Expect(ret).To(ContainElement(
+				slog.Group(fooAttrName,
+					slog.String("name", "id"),
+					slog.Group("options", slog.Bool("deprecated", true)),
+				),
+			))
+		})
+
+		Context("and a path is empty", func() {
+
+			It("panics", func() {
+				Expect(func() { slogctx.ProtoFieldMask("options.") }).To(
+					PanicWith(`invalid field mask path "options."`),
+				)
+			})
+		})
+	})
+
+	When("the message has repeated fields", func() {
+		enum := &descriptorpb.EnumDescriptorProto{
+			Name: proto.String("Color"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("RED"), Number: proto.Int32(0)},
+				{Name: proto.String("BLUE"), Number: proto.Int32(1)},
+			},
+		}
+		getter := slogctx.Proto(fooAttrName,
+			func(context.Context) (*descriptorpb.EnumDescriptorProto, bool) {
+				return enum, true
+			},
+			slogctx.ProtoFieldMask("value"),
+		)
+		ret := getter.GetAttrs(context.Background())
+
+		It("returns the elements as a slice", func() {
+			Expect(ret).To(ContainElement(
+				slog.Group(fooAttrName, slog.Any("value", []any{
+					map[string]any{"name": "RED", "number": int64(0)},
+					map[string]any{"name": "BLUE", "number": int64(1)},
+				})),
+			))
+		})
+
+		Context("and the field mask selects fields of the elements", func() {
+			getter := slogctx.Proto(fooAttrName,
+				func(context.Context) (*descriptorpb.EnumDescriptorProto, bool) {
+					return enum, true
+				},
+				slogctx.ProtoFieldMask("value.name"),
+			)
+			ret := getter.GetAttrs(context.Background())
+
+			It("returns only the selected fields of each element", func() {
+				Expect(ret).To(ContainElement(
+					slog.Group(fooAttrName, slog.Any("value", []any{
+						map[string]any{"name": "RED"},
+						map[string]any{"name": "BLUE"},
+					})),
+				))
+			})
+		})
+	})
+
+	When("the message is a timestamp", func() {
+		now := time.Now().UTC()
+		getter := slogctx.Proto(fooAttrName,
+			func(context.Context) (*timestamppb.Timestamp, bool) {
+				return timestamppb.New(now), true
+			},
+		)
+		ret := getter.GetAttrs(context.Background())
+
+		It("returns a time attribute", func() {
+			Expect(ret).To(ContainElement(slog.Time(fooAttrName, now)))
+		})
+	})
+
+	When("a field has the debug_redact option", func() {
+		msg := newSecretMessage("alice", "hunter2")
+		lookup := func(context.Context) (*dynamicpb.Message, bool) {
+			return msg, true
+		}
+
+		Context("and redaction is enabled", func() {
+			ret := slogctx.Proto(fooAttrName, lookup, slogctx.ProtoRedact()).
+				GetAttrs(context.Background())
+
+			It("replaces the field's value", func() {
+				Expect(ret).To(ContainElement(
+					slog.Group(fooAttrName,
+						slog.String("user", "alice"),
+						slog.String("token", slogctx.ProtoRedacted),
+					),
+				))
+			})
+		})
+
+		Context("and redaction is not enabled", func() {
+			ret := slogctx.Proto(fooAttrName, lookup).
+				GetAttrs(context.Background())
+
+			It("returns the field's value", func() {
+				Expect(ret).To(ContainElement(
+					slog.Group(fooAttrName,
+						slog.String("user", "alice"),
+						slog.String("token", "hunter2"),
+					),
+				))
+			})
+		})
+	})
+})
+
+func newSecretMessage(user, token string) *dynamicpb.Message {
+	str := descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum()
+	opt := descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("secret.proto"),
+		Package: proto.String("slogctx.test"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Secret"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{
+					Name:     proto.String("user"),
+					JsonName: proto.String("user"),
+					Number:   proto.Int32(1),
+					Type:     str,
+					Label:    opt,
+				},
+				{
+					Name:     proto.String("token"),
+					JsonName: proto.String("token"),
+					Number:   proto.Int32(2),
+					Type:     str,
+					Label:    opt,
+					Options: &descriptorpb.FieldOptions{
+						DebugRedact: proto.Bool(true),
+					},
+				},
+			},
+		}},
+	}, nil)
+	if err != nil {
+		panic(err)
+	}
+
+	desc := file.Messages().Get(0)
+	msg := dynamicpb.NewMessage(desc)
+	msg.Set(desc.Fields().ByName("user"), protoreflect.ValueOfString(user))
+	msg.Set(desc.Fields().ByName("token"), protoreflect.ValueOfString(token))
+	return msg
+}
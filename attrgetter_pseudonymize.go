@@ -0,0 +1,164 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+)
+
+// DefaultPseudonymLength is the number of bytes of the HMAC used in a
+// pseudonym if [PseudonymLength] is not used.
+const DefaultPseudonymLength = 16
+
+type (
+	// PseudonymKey is a secret key used by [Pseudonymize].
+	PseudonymKey struct {
+		// ID identifies the key, so that pseudonyms made with different
+		// keys can be told apart when keys are rotated. If set, it prefixes
+		// each pseudonym followed by a ':', e.g. "2024-01:9f86d081...".
+		ID string
+
+		// Secret is the HMAC key. It should be at least 32 random bytes.
+		Secret []byte
+	}
+
+	// PseudonymOption configures how [Pseudonymize] makes pseudonyms.
+	PseudonymOption func(*pseudonymConfig)
+
+	pseudonymConfig struct {
+		length int
+	}
+
+	pseudonymAttrGetter struct {
+		key    PseudonymKey
+		prefix string
+		pseudonymConfig
+		AttrGetter
+	}
+)
+
+// PseudonymLength sets the number of bytes, between 1 and 32, of the HMAC
+// used in a pseudonym. A pseudonym holds twice as many hex digits.
+//
+// Panics if n is out of range.
+func PseudonymLength(n int) PseudonymOption {
+	if n < 1 || n > sha256.Size {
+		panic("pseudonym length is out of range")
+	}
+
+	return func(c *pseudonymConfig) {
+		c.length = n
+	}
+}
+
+// Pseudonymize returns an [AttrGetter] that replaces the values returned by
+// another [AttrGetter] with pseudonyms, so that logs can be correlated by a
+// value, such as a user ID, without logging the value itself. A pseudonym
+// is the hex encoded HMAC-SHA256 of the value, so the same value always has
+// the same pseudonym for a key.
+//
+//	g := slogctx.Pseudonymize(
+//		slogctx.Attr("user", userpkg.IDFromCtx),
+//		slogctx.PseudonymKey{ID: "k1", Secret: secret},
+//	)
+//
+// Attributes that are not groups are replaced whatever their kind, using
+// their formatted value, while only the string values in groups are
+// replaced.
+//
+// Panics if the getter is nil or the key's secret is empty.
+func Pseudonymize(
+	attrGetter AttrGetter,
+	key PseudonymKey,
+	opts ...PseudonymOption,
+) AttrGetter {
+	if attrGetter == nil {
+		panic("AttrGetter is nil")
+	}
+	if len(key.Secret) == 0 {
+		panic("secret is empty")
+	}
+
+	p := &pseudonymAttrGetter{
+		key:             key,
+		pseudonymConfig: pseudonymConfig{length: DefaultPseudonymLength},
+		AttrGetter:      attrGetter,
+	}
+	if len(key.ID) > 0 {
+		p.prefix = key.ID + ":"
+	}
+	for _, opt := range opts {
+		opt(&p.pseudonymConfig)
+	}
+
+	return p
+}
+
+func (p *pseudonymAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return p.AppendAttrs(ctx, nil)
+}
+
+func (p *pseudonymAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	n := len(dst)
+	dst = appendAttrs(ctx, p.AttrGetter, dst)
+	for i := n; i < len(dst); i++ {
+		a := dst[i]
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			a.Value = p.pseudonymizeGroup(a.Value.Group())
+		} else {
+			a.Value = slog.StringValue(p.pseudonym(a.Value.String()))
+		}
+		dst[i] = a
+	}
+
+	return dst
+}
+
+// pseudonymizeGroup returns a group holding a copy of attrs, as they may be
+// shared with the getter, with pseudonyms for the string values.
+func (p *pseudonymAttrGetter) pseudonymizeGroup(attrs []slog.Attr) slog.Value {
+	group := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		a.Value = a.Value.Resolve()
+		switch a.Value.Kind() {
+		case slog.KindString:
+			a.Value = slog.StringValue(p.pseudonym(a.Value.String()))
+		case slog.KindGroup:
+			a.Value = p.pseudonymizeGroup(a.Value.Group())
+		}
+		group[i] = a
+	}
+
+	return slog.GroupValue(group...)
+}
+
+func (p *pseudonymAttrGetter) pseudonym(s string) string {
+	mac := hmac.New(sha256.New, p.key.Secret)
+	mac.Write([]byte(s))
+	sum := mac.Sum(nil)
+
+	buf := make([]byte, len(p.prefix)+hex.EncodedLen(p.length))
+	n := copy(buf, p.prefix)
+	hex.Encode(buf[n:], sum[:p.length])
+	return string(buf)
+}
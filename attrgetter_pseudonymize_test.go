@@ -0,0 +1,109 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Pseudonymizing attributes", func() {
+	ctx := context.Background()
+	key := slogctx.PseudonymKey{Secret: []byte("key")}
+
+	getter := func(attrs ...slog.Attr) slogctx.AttrGetter {
+		return slogctx.AttrGetterFunc(func(context.Context) []slog.Attr {
+			return attrs
+		})
+	}
+
+	// HMAC-SHA256("key", "u1") begins dc0708706ec6b9b1224ee4d159bdf545.
+	const pseudonym = "dc0708706ec6b9b1224ee4d159bdf545"
+
+	It("replaces a value with its HMAC", func() {
+		g := slogctx.Pseudonymize(getter(slog.String("user", "u1")), key)
+		Expect(g.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("user", pseudonym),
+		}))
+	})
+
+	It("replaces a value of another kind using its formatted value", func() {
+		g := slogctx.Pseudonymize(getter(slog.Any("user", "u1")), key)
+		Expect(g.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("user", pseudonym),
+		}))
+	})
+
+	It("limits the length of the pseudonym", func() {
+		g := slogctx.Pseudonymize(getter(slog.String("user", "u1")), key,
+			slogctx.PseudonymLength(4),
+		)
+		Expect(g.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("user", pseudonym[:8]),
+		}))
+	})
+
+	It("prefixes the pseudonym with the key ID", func() {
+		g := slogctx.Pseudonymize(getter(slog.String("user", "u1")),
+			slogctx.PseudonymKey{ID: "k1", Secret: key.Secret},
+		)
+		Expect(g.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("user", "k1:"+pseudonym),
+		}))
+	})
+
+	It("gives different pseudonyms for different keys", func() {
+		g := slogctx.Pseudonymize(getter(slog.String("user", "u1")),
+			slogctx.PseudonymKey{Secret: []byte("other")},
+		)
+		Expect(g.GetAttrs(ctx)).NotTo(Equal([]slog.Attr{
+			slog.String("user", pseudonym),
+		}))
+	})
+
+	It("replaces only the string values in groups without modifying the getter's", func() {
+		group := []slog.Attr{slog.String("id", "u1"), slog.Bool("admin", true)}
+		g := slogctx.Pseudonymize(getter(slog.Attr{
+			Key:   "user",
+			Value: slog.GroupValue(group...),
+		}), key)
+
+		Expect(g.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.Group("user", slog.String("id", pseudonym), slog.Bool("admin", true)),
+		}))
+		Expect(group[0]).To(Equal(slog.String("id", "u1")))
+	})
+
+	When("the secret is empty", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Pseudonymize(fooGetter, slogctx.PseudonymKey{}) }).
+				To(PanicWith("secret is empty"))
+		})
+	})
+
+	When("the length is out of range", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.PseudonymLength(33) }).
+				To(PanicWith("pseudonym length is out of range"))
+		})
+	})
+})
@@ -0,0 +1,154 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type sanitizeAttrGetter struct {
+	AttrGetter
+}
+
+// Sanitize returns an [AttrGetter] that escapes characters that could be
+// used to forge, or hide, log output in the keys and string values of the
+// attributes returned by one or more [AttrGetter] instances. Values in
+// groups are escaped too.
+//
+// Control characters (including newlines and the escape character that
+// starts ANSI escape sequences), Unicode bidirectional formatting
+// characters, line and paragraph separators, and invalid UTF-8 are replaced
+// by Go-style escapes such as `\n`, `\x1b` and `\u202e`. Backslashes are
+// escaped as `\\`, so escapes cannot be confused with text that looks like
+// them.
+//
+// Values of other kinds, e.g. those created by [log/slog.Any], are left as
+// they are after resolving any [log/slog.LogValuer].
+//
+// Panics if receives zero [AttrGetter] instances, or any [AttrGetter]
+// references are nil.
+func Sanitize(attrGetters ...AttrGetter) AttrGetter {
+	return &sanitizeAttrGetter{
+		AttrGetter: concat(attrGetters),
+	}
+}
+
+func (s *sanitizeAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return s.AppendAttrs(ctx, nil)
+}
+
+func (s *sanitizeAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	n := len(dst)
+	dst = appendAttrs(ctx, s.AttrGetter, dst)
+	for i := n; i < len(dst); i++ {
+		dst[i] = sanitizeAttr(dst[i])
+	}
+
+	return dst
+}
+
+func sanitizeAttr(a slog.Attr) slog.Attr {
+	a.Key = sanitizeString(a.Key)
+	a.Value = a.Value.Resolve()
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		a.Value = slog.StringValue(sanitizeString(a.Value.String()))
+
+	case slog.KindGroup:
+		// The group's attributes may be shared with the getter, so they are
+		// copied rather than modified.
+		group := a.Value.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			attrs[i] = sanitizeAttr(ga)
+		}
+		a.Value = slog.GroupValue(attrs...)
+	}
+
+	return a
+}
+
+// sanitizeString returns s with unsafe characters escaped, or s itself if
+// there are none.
+func sanitizeString(s string) string {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isUnsafeRune(r, size) {
+			return escapeString(s, i)
+		}
+		i += size
+	}
+
+	return s
+}
+
+// escapeString escapes the unsafe characters in s, the first of which is
+// at index i.
+func escapeString(s string, i int) string {
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	b.WriteString(s[:i])
+
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case !isUnsafeRune(r, size):
+			b.WriteString(s[i : i+size])
+		case r == '\n':
+			b.WriteString(`\n`)
+		case r == '\r':
+			b.WriteString(`\r`)
+		case r == '\t':
+			b.WriteString(`\t`)
+		case r == '\\':
+			b.WriteString(`\\`)
+		case size == 1:
+			// Invalid UTF-8 or an ASCII control character.
+			b.WriteString(`\x`)
+			b.WriteByte(hexDigits[s[i]>>4])
+			b.WriteByte(hexDigits[s[i]&0xf])
+		default:
+			b.WriteString(`\u`)
+			for shift := 12; shift >= 0; shift -= 4 {
+				b.WriteByte(hexDigits[(r>>shift)&0xf])
+			}
+		}
+		i += size
+	}
+
+	return b.String()
+}
+
+const hexDigits = "0123456789abcdef"
+
+func isUnsafeRune(r rune, size int) bool {
+	switch {
+	case r == utf8.RuneError && size == 1:
+		return true
+	case r < utf8.RuneSelf:
+		return r < 0x20 || r == 0x7f || r == '\\'
+	}
+
+	return unicode.IsControl(r) || unicode.Is(unicode.Bidi_Control, r) ||
+		r == '\u2028' || r == '\u2029'
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sanitizing attributes", func() {
+	ctx := context.Background()
+
+	sanitize := func(attrs ...slog.Attr) []slog.Attr {
+		getter := slogctx.AttrGetterFunc(func(context.Context) []slog.Attr {
+			return attrs
+		})
+		return slogctx.Sanitize(getter).GetAttrs(ctx)
+	}
+
+	DescribeTable("escaping string values",
+		func(value, expected string) {
+			Expect(sanitize(slog.String(fooAttrName, value))).To(
+				Equal([]slog.Attr{slog.String(fooAttrName, expected)}),
+			)
+		},
+		Entry("safe", "GET /index.html", "GET /index.html"),
+		Entry("non-ASCII", "héllo, 世界", "héllo, 世界"),
+		Entry("newline", "a\nlevel=ERROR msg=forged", `a\nlevel=ERROR msg=forged`),
+		Entry("carriage return and tab", "a\r\tb", `a\r\tb`),
+		Entry("ANSI escape", "\x1b[31mred", `\x1b[31mred`),
+		Entry("DEL", "a\x7fb", `a\x7fb`),
+		Entry("C1 control", "a\u0085b", `a\u0085b`),
+		Entry("bidi override", "abc\u202egpj.exe", `abc\u202egpj.exe`),
+		Entry("bidi isolate", "\u2066x\u2069", `\u2066x\u2069`),
+		Entry("line separator", "a\u2028b", `a\u2028b`),
+		Entry("invalid UTF-8", "a\xffb", `a\xffb`),
+		Entry("backslash", `a\nb\x1b`, `a\\nb\\x1b`),
+	)
+
+	It("escapes keys", func() {
+		Expect(sanitize(slog.String("a\nb", barAttrValue))).To(
+			Equal([]slog.Attr{slog.String(`a\nb`, barAttrValue)}),
+		)
+	})
+
+	It("escapes attributes in groups", func() {
+		group := slog.Group(groupName,
+			slog.String("x", "1\n2"),
+			slog.Group("y", slog.String("z\r", "3")),
+		)
+		Expect(sanitize(group)).To(Equal([]slog.Attr{
+			slog.Group(groupName,
+				slog.String("x", `1\n2`),
+				slog.Group("y", slog.String(`z\r`, "3")),
+			),
+		}))
+	})
+
+	It("does not modify the getter's groups", func() {
+		group := slog.Group(groupName, slog.String("x", "1\n2"))
+		sanitize(group)
+		Expect(group.Value.Group()[0]).To(Equal(slog.String("x", "1\n2")))
+	})
+
+	It("leaves other kinds of values unchanged", func() {
+		Expect(sanitize(fooAttr, pifAttr)).To(Equal([]slog.Attr{fooAttr, pifAttr}))
+	})
+
+	When("passing zero AttrGetter instances", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Sanitize() }).
+				To(PanicWith("received 0 AttrGetters"))
+		})
+	})
+})
@@ -17,7 +17,6 @@ package slogctx
 import (
 	"context"
 	"log/slog"
-	"reflect"
 	"time"
 )
 
@@ -27,13 +26,24 @@ type typeAttrGetter[T any] struct {
 	makeAttr func(string, T) slog.Attr
 }
 
+func (l *typeAttrGetter[T]) attrKey() string {
+	return l.key
+}
+
 func (l *typeAttrGetter[T]) GetAttrs(ctx context.Context) []slog.Attr {
+	return l.AppendAttrs(ctx, nil)
+}
+
+func (l *typeAttrGetter[T]) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
 	t, ok := l.lookup(ctx)
 	if !ok {
-		return nil
+		return dst
 	}
 
-	return []slog.Attr{l.makeAttr(l.key, t)}
+	return append(dst, l.makeAttr(l.key, t))
 }
 
 // Attr returns an [AttrGetter] that takes its value from a [context.Context].
@@ -131,33 +141,3 @@ func Attr[T any](
 		}
 	}
 }
-
-// IgnoreZero returns a function suitable for [Attr] that will return a
-// false status if the value returned by lookup is the type's 'zero' value.
-func IgnoreZero[T any](
-	lookup func(context.Context) T,
-) func(context.Context) (value T, ok bool) {
-	if lookup == nil {
-		panic("lookup is nil")
-	}
-
-	return func(ctx context.Context) (T, bool) {
-		t := lookup(ctx)
-		return t, !reflect.ValueOf(t).IsZero()
-	}
-}
-
-// LogZero returns a function suitable for [Attr] that will always return
-// a true status. Useful if the type's 'zero' value should be included in
-// logged output.
-func LogZero[T any](
-	lookup func(context.Context) T,
-) func(context.Context) (value T, ok bool) {
-	if lookup == nil {
-		panic("lookup is nil")
-	}
-
-	return func(ctx context.Context) (T, bool) {
-		return lookup(ctx), true
-	}
-}
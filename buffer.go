@@ -0,0 +1,236 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+// BufferOptions are options for a [BufferHandler]. A zero BufferOptions
+// consists entirely of default values.
+type BufferOptions struct {
+	// Threshold is the lowest level of the records passed to the target
+	// handler immediately. Records below it are buffered. If nil,
+	// [log/slog.LevelInfo] is used.
+	Threshold slog.Leveler
+
+	// FlushLevel is the lowest level of the records that flush the buffer.
+	// If nil, [log/slog.LevelError] is used.
+	FlushLevel slog.Leveler
+
+	// MaxRecords is the most records buffered for a context. Once it is
+	// reached, the oldest record is dropped for each new one. If zero, 100
+	// is used.
+	MaxRecords int
+}
+
+// BufferHandler is a "fingers crossed" handler. It buffers the records,
+// below a threshold level, logged with a context in a scope started by
+// [WithRecordBuffer], e.g. for a request. If a record at the flush level or
+// above is logged in the scope, the buffered records are passed to the
+// target handler before it, and later records in the scope are passed
+// straight through. Otherwise the buffered records are thrown away with the
+// context, so there is full detail for failing requests at little cost for
+// the others.
+//
+// Records below the threshold that are logged with a context outside of a
+// scope are dropped.
+//
+// If the target is a [Handler], the context attributes of a buffered record
+// are taken when it is logged rather than when it is flushed, so values
+// such as those of [Sequence] and [Elapsed] are those at the time of
+// logging. Buffered records carry all the context attributes, even if
+// [HandlerOptions.EmitOnce] is set.
+type BufferHandler struct {
+	target slog.Handler
+	opts   BufferOptions
+}
+
+var _ slog.Handler = (*BufferHandler)(nil)
+
+type bufferCtxKey struct{}
+
+// recordBuffer holds the records buffered in a scope.
+type recordBuffer struct {
+	mu        sync.Mutex
+	entries   []bufferedRecord
+	triggered bool
+
+	// flushMu is held while passing records in the scope to the target
+	// handlers, so that records logged while the buffer is flushed are
+	// passed after the buffered ones.
+	flushMu sync.Mutex
+}
+
+// bufferedRecord is a record and the handler and context to handle it
+// with.
+type bufferedRecord struct {
+	target slog.Handler
+	ctx    context.Context
+	rec    slog.Record
+}
+
+// WithRecordBuffer returns a copy of ctx that starts a scope, including
+// contexts derived from the returned context, in which a [BufferHandler]
+// buffers records.
+func WithRecordBuffer(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bufferCtxKey{}, &recordBuffer{})
+}
+
+// NewBufferHandler returns a new BufferHandler that passes records to the
+// target. The target handler should be configured to handle all levels. If
+// opts is nil, the default options are used.
+//
+// Panics if target handler is nil, or MaxRecords is negative.
+func NewBufferHandler(target slog.Handler, opts *BufferOptions) *BufferHandler {
+	if target == nil {
+		panic("target is nil")
+	}
+
+	h := &BufferHandler{target: target}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Threshold == nil {
+		h.opts.Threshold = slog.LevelInfo
+	}
+	if h.opts.FlushLevel == nil {
+		h.opts.FlushLevel = slog.LevelError
+	}
+	switch {
+	case h.opts.MaxRecords < 0:
+		panic("MaxRecords is negative")
+	case h.opts.MaxRecords == 0:
+		h.opts.MaxRecords = 100
+	}
+
+	return h
+}
+
+// Enabled returns whether the handler is enabled for the context and level.
+// Records below the threshold are enabled if the context is in a scope and
+// the target handler is enabled for them.
+func (h *BufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	if level < h.opts.Threshold.Level() {
+		if _, ok := ctx.Value(bufferCtxKey{}).(*recordBuffer); !ok {
+			return false
+		}
+	}
+	return h.target.Enabled(ctx, level)
+}
+
+// Handle buffers the record, or passes it to the target handler after
+// flushing the buffer of the context's scope if the record is at the flush
+// level or above.
+func (h *BufferHandler) Handle(ctx context.Context, rec slog.Record) error {
+	buf, ok := ctx.Value(bufferCtxKey{}).(*recordBuffer)
+	if !ok {
+		if rec.Level < h.opts.Threshold.Level() {
+			return nil
+		}
+		return h.target.Handle(ctx, rec)
+	}
+
+	buf.mu.Lock()
+	if rec.Level >= h.opts.FlushLevel.Level() && !buf.triggered {
+		buf.triggered = true
+		entries := buf.entries
+		buf.entries = nil
+		buf.flushMu.Lock()
+		buf.mu.Unlock()
+		defer buf.flushMu.Unlock()
+
+		return errors.Join(flushRecords(entries), h.target.Handle(ctx, rec))
+	}
+
+	if buf.triggered || rec.Level >= h.opts.Threshold.Level() {
+		buf.mu.Unlock()
+		buf.flushMu.Lock()
+		defer buf.flushMu.Unlock()
+		return h.target.Handle(ctx, rec)
+	}
+
+	if len(buf.entries) >= h.opts.MaxRecords {
+		n := len(buf.entries) - h.opts.MaxRecords + 1
+		clear(buf.entries[:n])
+		buf.entries = buf.entries[n:]
+	}
+	buf.entries = append(buf.entries, bufferRecord(ctx, h.target, rec))
+	buf.mu.Unlock()
+	return nil
+}
+
+// bufferRecord returns the record to buffer. If the target is a *Handler,
+// the record holds the context attributes at the time of logging and is
+// to be passed to the handler's target.
+func bufferRecord(
+	ctx context.Context,
+	target slog.Handler,
+	rec slog.Record,
+) bufferedRecord {
+	for {
+		h, ok := target.(*Handler)
+		if !ok {
+			break
+		}
+		rec = h.resolve(ctx, rec, false)
+		target = h.target
+	}
+
+	return bufferedRecord{
+		target: target,
+		ctx:    ctx,
+		rec:    rec.Clone(),
+	}
+}
+
+func flushRecords(entries []bufferedRecord) error {
+	var errs []error
+	for _, e := range entries {
+		if !e.target.Enabled(e.ctx, e.rec.Level) {
+			continue
+		}
+		if err := e.target.Handle(e.ctx, e.rec); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs returns a handler whose target includes the given attributes.
+func (h *BufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.target = h.target.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup returns a handler whose target groups attributes. If the name
+// is empty, WithGroup returns the receiver.
+func (h *BufferHandler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.target = h.target.WithGroup(name)
+	return &h2
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Buffering records until an error", func() {
+	var (
+		target *recordsHandler
+		logger *slog.Logger
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		target = &recordsHandler{}
+		logger = slog.New(slogctx.NewBufferHandler(target, &slogctx.BufferOptions{
+			MaxRecords: 2,
+		}))
+		ctx = slogctx.WithRecordBuffer(context.Background())
+	})
+
+	messages := func() []string {
+		var msgs []string
+		for _, rec := range target.records() {
+			msgs = append(msgs, rec.Message)
+		}
+		return msgs
+	}
+
+	It("passes records at the threshold or above straight through", func() {
+		logger.DebugContext(ctx, "debug")
+		logger.InfoContext(ctx, "info")
+
+		Expect(messages()).To(Equal([]string{"info"}))
+	})
+
+	It("flushes the most recent buffered records before an error", func() {
+		logger.DebugContext(ctx, "debug 1")
+		logger.DebugContext(ctx, "debug 2")
+		logger.DebugContext(ctx, "debug 3")
+		logger.ErrorContext(ctx, "error")
+		logger.DebugContext(ctx, "debug 4")
+
+		Expect(messages()).To(Equal([]string{"debug 2", "debug 3", "error", "debug 4"}))
+	})
+
+	It("replays records with the attributes and groups of their logger", func() {
+		var out bytes.Buffer
+		logger := slog.New(slogctx.NewBufferHandler(
+			slog.NewJSONHandler(&out, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+					if a.Key == slog.TimeKey && len(groups) == 0 {
+						return slog.Attr{}
+					}
+					return a
+				},
+			}),
+			nil,
+		))
+		logger.WithGroup("g").With("a", 1).DebugContext(ctx, "debug", "b", 2)
+		logger.ErrorContext(ctx, "error")
+
+		Expect(out.String()).To(Equal(
+			`{"level":"DEBUG","msg":"debug","g":{"a":1,"b":2}}` + "\n" +
+				`{"level":"ERROR","msg":"error"}` + "\n",
+		))
+	})
+
+	It("passes records logged during a flush after the buffered records", func() {
+		gated := &gatedHandler{
+			recordsHandler: target,
+			started:        make(chan struct{}),
+			release:        make(chan struct{}),
+		}
+		logger := slog.New(slogctx.NewBufferHandler(gated, nil))
+		logger.DebugContext(ctx, "debug")
+
+		flushed := make(chan struct{})
+		go func() {
+			defer close(flushed)
+			logger.ErrorContext(ctx, "error")
+		}()
+		<-gated.started
+
+		logged := make(chan struct{})
+		go func() {
+			defer close(logged)
+			logger.InfoContext(ctx, "info")
+		}()
+		Consistently(logged, 50*time.Millisecond).ShouldNot(BeClosed())
+
+		close(gated.release)
+		<-flushed
+		<-logged
+		Expect(messages()).To(Equal([]string{"debug", "error", "info"}))
+	})
+
+	It("keeps the buffers of separate scopes apart", func() {
+		other := slogctx.WithRecordBuffer(context.Background())
+		logger.DebugContext(other, "other")
+		logger.DebugContext(ctx, "debug")
+		logger.ErrorContext(ctx, "error")
+
+		Expect(messages()).To(Equal([]string{"debug", "error"}))
+	})
+
+	When("the target is a Handler", func() {
+
+		BeforeEach(func() {
+			ctx = slogctx.WithEmitOnce(slogctx.WithSequence(ctx))
+			ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+		})
+
+		It("takes the context attributes when records are logged", func() {
+			logger := slog.New(slogctx.NewBufferHandler(
+				slogctx.NewHandler(target, slogctx.Sequence("seq")),
+				nil,
+			))
+			logger.DebugContext(ctx, "step 1")
+			logger.InfoContext(ctx, "step 2")
+			logger.ErrorContext(ctx, "step 3")
+
+			recs := target.records()
+			Expect(messages()).To(Equal([]string{"step 2", "step 1", "step 3"}))
+			Expect(GetAttrs(recs[0])).To(Equal([]slog.Attr{slog.Uint64("seq", 2)}))
+			Expect(GetAttrs(recs[1])).To(Equal([]slog.Attr{slog.Uint64("seq", 1)}))
+			Expect(GetAttrs(recs[2])).To(Equal([]slog.Attr{slog.Uint64("seq", 3)}))
+		})
+
+		It("adds all the context attributes to buffered records", func() {
+			logger := slog.New(slogctx.NewBufferHandler(
+				slogctx.NewHandlerWithOptions(target,
+					&slogctx.HandlerOptions{EmitOnce: true},
+					barGetter,
+				),
+				nil,
+			))
+			logger.DebugContext(ctx, "debug")
+			logger.InfoContext(ctx, "info 1")
+			logger.InfoContext(ctx, "info 2")
+			logger.ErrorContext(ctx, "error")
+
+			var attrs [][]slog.Attr
+			for _, rec := range target.records() {
+				attrs = append(attrs, GetAttrs(rec))
+			}
+			Expect(messages()).To(Equal([]string{"info 1", "info 2", "debug", "error"}))
+			Expect(attrs).To(Equal([][]slog.Attr{{barAttr}, {}, {barAttr}, {}}))
+		})
+	})
+
+	When("the context is not in a scope", func() {
+
+		It("drops records below the threshold", func() {
+			logger.Debug("debug")
+			logger.Error("error")
+
+			Expect(messages()).To(Equal([]string{"error"}))
+		})
+	})
+
+	When("MaxRecords is negative", func() {
+
+		It("panics", func() {
+			Expect(func() {
+				slogctx.NewBufferHandler(target, &slogctx.BufferOptions{MaxRecords: -1})
+			}).To(PanicWith("MaxRecords is negative"))
+		})
+	})
+})
+
+// gatedHandler is a recordsHandler that blocks handling the record with the
+// message "debug" until release is closed.
+type gatedHandler struct {
+	*recordsHandler
+	started chan struct{}
+	release chan struct{}
+}
+
+func (h *gatedHandler) Handle(ctx context.Context, rec slog.Record) error {
+	if rec.Message == "debug" {
+		close(h.started)
+		<-h.release
+	}
+	return h.recordsHandler.Handle(ctx, rec)
+}
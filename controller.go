@@ -0,0 +1,192 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// Controller is an [AttrGetter] whose getters can be replaced, or disabled
+// by key, while it is in use. Pass it to [NewHandler] and every handler
+// derived from that handler will see the changes.
+//
+//	ctrl := slogctx.NewController(getters...)
+//	log := slog.New(slogctx.NewHandler(h, ctrl))
+//	// ...
+//	ctrl.SetDisabled(strings.Split(os.Getenv("LOG_CTX_DISABLED"), ",")...)
+//
+// The key of a getter created by [Attr], [Group] or [Proto] is the key it was
+// created with. Disabling a key also removes any attribute with that key
+// returned by other getters, e.g. an [AttrGetterFunc].
+//
+// A Controller is safe for concurrent use.
+type Controller struct {
+	mu    sync.Mutex
+	state atomic.Pointer[controllerState]
+}
+
+type controllerState struct {
+	getters  []AttrGetter
+	disabled map[string]struct{}
+
+	// enabled holds the getters not disabled by key, and filter reports
+	// whether attributes from getters without a key must be checked.
+	enabled []AttrGetter
+	filter  bool
+}
+
+var _ AttrAppender = (*Controller)(nil)
+
+// NewController returns a new Controller using the getters.
+//
+// Panics if any [AttrGetter] references are nil.
+func NewController(attrGetters ...AttrGetter) *Controller {
+	c := &Controller{}
+	c.SetGetters(attrGetters...)
+	return c
+}
+
+// GetAttrs returns the attributes from the enabled getters.
+func (c *Controller) GetAttrs(ctx context.Context) []slog.Attr {
+	return c.AppendAttrs(ctx, nil)
+}
+
+// AppendAttrs appends the attributes from the enabled getters to dst.
+func (c *Controller) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	s := c.state.Load()
+	n := len(dst)
+	for _, g := range s.enabled {
+		dst = appendAttrs(ctx, g, dst)
+	}
+
+	if !s.filter {
+		return dst
+	}
+	kept := slices.DeleteFunc(dst[n:], func(a slog.Attr) bool {
+		_, ok := s.disabled[a.Key]
+		return ok
+	})
+	return dst[:n+len(kept)]
+}
+
+// SetGetters replaces the getters. Keys disabled before the call remain
+// disabled.
+//
+// Panics if any [AttrGetter] references are nil.
+func (c *Controller) SetGetters(attrGetters ...AttrGetter) {
+	validateAttrGetters(attrGetters)
+
+	c.update(func(s *controllerState) {
+		s.getters = slices.Clone(attrGetters)
+	})
+}
+
+// Getters returns the current getters, including any that are disabled.
+func (c *Controller) Getters() []AttrGetter {
+	return slices.Clone(c.state.Load().getters)
+}
+
+// Keys returns the keys of the current getters that have one. See
+// [Controller] for how a getter's key is determined.
+func (c *Controller) Keys() []string {
+	getters := c.state.Load().getters
+	keys := make([]string, 0, len(getters))
+	for _, g := range getters {
+		if k, ok := g.(keyedAttrGetter); ok {
+			keys = append(keys, k.attrKey())
+		}
+	}
+
+	return keys
+}
+
+// Disable disables the getters, and attributes, with the keys.
+func (c *Controller) Disable(keys ...string) {
+	c.update(func(s *controllerState) {
+		for _, k := range keys {
+			s.disabled[k] = struct{}{}
+		}
+	})
+}
+
+// Enable enables the getters, and attributes, with the keys.
+func (c *Controller) Enable(keys ...string) {
+	c.update(func(s *controllerState) {
+		for _, k := range keys {
+			delete(s.disabled, k)
+		}
+	})
+}
+
+// SetDisabled replaces the set of disabled keys, enabling any key that is
+// not given. Empty keys are ignored, so the result of splitting an empty
+// string can be passed directly.
+func (c *Controller) SetDisabled(keys ...string) {
+	c.update(func(s *controllerState) {
+		clear(s.disabled)
+		for _, k := range keys {
+			s.disabled[k] = struct{}{}
+		}
+	})
+}
+
+// Disabled returns the disabled keys.
+func (c *Controller) Disabled() []string {
+	disabled := c.state.Load().disabled
+	keys := make([]string, 0, len(disabled))
+	for k := range disabled {
+		keys = append(keys, k)
+	}
+
+	slices.Sort(keys)
+	return keys
+}
+
+// update applies fn to a copy of the current state and then stores it.
+func (c *Controller) update(fn func(*controllerState)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := &controllerState{disabled: map[string]struct{}{}}
+	if old := c.state.Load(); old != nil {
+		s.getters = old.getters
+		for k := range old.disabled {
+			s.disabled[k] = struct{}{}
+		}
+	}
+
+	fn(s)
+	delete(s.disabled, "")
+
+	s.enabled = make([]AttrGetter, 0, len(s.getters))
+	for _, g := range s.getters {
+		k, ok := g.(keyedAttrGetter)
+		if !ok {
+			s.filter = s.filter || len(s.disabled) > 0
+		} else if _, off := s.disabled[k.attrKey()]; off {
+			continue
+		}
+		s.enabled = append(s.enabled, g)
+	}
+
+	c.state.Store(s)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controlling the getters used by a handler", func() {
+	var (
+		ctrl       *slogctx.Controller
+		spyHandler *HandlerSpy
+		handler    slog.Handler
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+		ctx = context.WithValue(ctx, pifCtxKey, pifAttrValue)
+
+		ctrl = slogctx.NewController(fooGetter, barGetter)
+		spyHandler = NewHandlerSpy()
+		handler = slogctx.NewHandler(spyHandler, ctrl)
+	})
+
+	handle := func(h slog.Handler) []slog.Attr {
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+		Expect(h.Handle(ctx, rec)).To(Succeed())
+		return GetAttrs(spyHandler.HandleSpy.Rec)
+	}
+
+	It("adds the attributes from the getters", func() {
+		Expect(handle(handler)).To(ConsistOf(fooAttr, barAttr))
+	})
+
+	It("returns the keys of the getters", func() {
+		Expect(ctrl.Keys()).To(Equal([]string{fooAttrName, barAttrName}))
+	})
+
+	When("the getters are replaced", func() {
+		var derived slog.Handler
+
+		BeforeEach(func() {
+			derived = handler.WithGroup(groupName).WithAttrs([]slog.Attr{pifAttr})
+			ctrl.SetGetters(pifGetter)
+		})
+
+		It("uses the new getters", func() {
+			Expect(handle(handler)).To(ConsistOf(pifAttr))
+		})
+
+		It("uses the new getters in derived handlers", func() {
+			Expect(handle(derived)).To(ConsistOf(pifAttr))
+		})
+	})
+
+	When("a key is disabled", func() {
+
+		BeforeEach(func() {
+			ctrl.Disable(fooAttrName)
+		})
+
+		It("omits the attribute", func() {
+			Expect(handle(handler)).To(ConsistOf(barAttr))
+		})
+
+		It("reports the key as disabled", func() {
+			Expect(ctrl.Disabled()).To(Equal([]string{fooAttrName}))
+		})
+
+		Context("and the getters are replaced", func() {
+
+			BeforeEach(func() {
+				ctrl.SetGetters(fooGetter, pifGetter)
+			})
+
+			It("keeps the key disabled", func() {
+				Expect(handle(handler)).To(ConsistOf(pifAttr))
+			})
+		})
+
+		Context("and then enabled", func() {
+
+			BeforeEach(func() {
+				ctrl.Enable(fooAttrName)
+			})
+
+			It("includes the attribute", func() {
+				Expect(handle(handler)).To(ConsistOf(fooAttr, barAttr))
+			})
+		})
+	})
+
+	When("the key of an attribute from a getter without a key is disabled", func() {
+
+		BeforeEach(func() {
+			ctrl.SetGetters(slogctx.AttrGetterFunc(func(context.Context) []slog.Attr {
+				return []slog.Attr{fooAttr, barAttr, pifAttr}
+			}))
+			ctrl.Disable(barAttrName)
+		})
+
+		It("omits the attribute", func() {
+			Expect(handle(handler)).To(ConsistOf(fooAttr, pifAttr))
+		})
+	})
+
+	When("the disabled keys are replaced", func() {
+
+		BeforeEach(func() {
+			ctrl.Disable(fooAttrName)
+			ctrl.SetDisabled(barAttrName, "")
+		})
+
+		It("only disables the given keys", func() {
+			Expect(ctrl.Disabled()).To(Equal([]string{barAttrName}))
+			Expect(handle(handler)).To(ConsistOf(fooAttr))
+		})
+	})
+
+	When("passing nil for an AttrGetter", func() {
+
+		It("panics", func() {
+			Expect(func() { ctrl.SetGetters(fooGetter, nil) }).
+				To(PanicWith("AttrGetter 2 of 2 is nil"))
+		})
+	})
+})
@@ -0,0 +1,67 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+)
+
+type correlationCtxKey struct{}
+
+// correlationID is a correlation ID and the number of children forked
+// from it.
+type correlationID struct {
+	id       string
+	children atomic.Uint64
+}
+
+// WithCorrelationID returns a copy of ctx with the correlation ID, e.g. one
+// made by the id package.
+//
+// Panics if id is empty.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	if len(id) == 0 {
+		panic("id is empty")
+	}
+	return context.WithValue(ctx, correlationCtxKey{}, &correlationID{id: id})
+}
+
+// CorrelationID returns the correlation ID of ctx, if any. It can be used
+// as the lookup of [Attr].
+func CorrelationID(ctx context.Context) (string, bool) {
+	c, ok := ctx.Value(correlationCtxKey{}).(*correlationID)
+	if !ok {
+		return "", false
+	}
+	return c.id, true
+}
+
+// Fork returns a copy of ctx with a child of its correlation ID, for work
+// such as a goroutine that is part of a request. The children of an ID are
+// numbered from 1, e.g. "abc123.1" and "abc123.2" are the first two
+// children of "abc123", and "abc123.2.1" is the first child of "abc123.2".
+// Fork is safe for concurrent use. If ctx has no correlation ID, Fork
+// returns ctx.
+func Fork(ctx context.Context) context.Context {
+	parent, ok := ctx.Value(correlationCtxKey{}).(*correlationID)
+	if !ok {
+		return ctx
+	}
+
+	id := parent.id + "." + strconv.FormatUint(parent.children.Add(1), 10)
+	return context.WithValue(ctx, correlationCtxKey{}, &correlationID{id: id})
+}
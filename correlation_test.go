@@ -0,0 +1,60 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Forking correlation IDs", func() {
+
+	idOf := func(ctx context.Context) string {
+		id, ok := slogctx.CorrelationID(ctx)
+		Expect(ok).To(BeTrue())
+		return id
+	}
+
+	It("numbers the children of an ID", func() {
+		ctx := slogctx.WithCorrelationID(context.Background(), "abc123")
+		child1 := slogctx.Fork(ctx)
+		child2 := slogctx.Fork(ctx)
+
+		Expect(idOf(ctx)).To(Equal("abc123"))
+		Expect(idOf(child1)).To(Equal("abc123.1"))
+		Expect(idOf(child2)).To(Equal("abc123.2"))
+		Expect(idOf(slogctx.Fork(child2))).To(Equal("abc123.2.1"))
+	})
+
+	When("the context has no ID", func() {
+
+		It("returns the context", func() {
+			ctx := context.Background()
+			Expect(slogctx.Fork(ctx)).To(BeIdenticalTo(ctx))
+		})
+	})
+
+	When("the ID is empty", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.WithCorrelationID(context.Background(), "") }).
+				To(PanicWith("id is empty"))
+		})
+	})
+})
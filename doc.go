@@ -55,6 +55,13 @@
 //	// or
 //	g := slogctx.Attr("d", slogctx.LogZero(dpkg.FromCtx))
 //
+// [IgnoreEmpty], [IgnoreNil] and [OmitIf] offer other ways to decide whether
+// a value should be omitted, and [Default] logs a placeholder in place of a
+// missing value.
+//
+//	// epkg.FromCtx is a func(context.Context) string
+//	g := slogctx.Attr("e", slogctx.Default(slogctx.IgnoreEmpty(epkg.FromCtx), "unknown"))
+//
 // Use [Group] to assign all retrieved attributes to a named group.
 //
 //	g := slogctx.Group("config",
@@ -65,6 +72,29 @@
 //	// Will log attributes as "config.hostname", etc. or however the target
 //	// handler formats grouped attributes.
 //
+// Use [Proto] to log a protobuf message as a group of attributes.
+//
+//	// tenantpb.FromCtx is a func(context.Context) (*tenantpb.Tenant, bool)
+//	g := slogctx.Proto("tenant", tenantpb.FromCtx,
+//		slogctx.ProtoFieldMask("id", "plan.name"),
+//		slogctx.ProtoRedact(),
+//	)
+//
+// Use [Sanitize] to escape newlines, terminal escape sequences, etc. in
+// values that come from untrusted sources, such as request headers.
+//
+//	g := slogctx.Sanitize(
+//		slogctx.Attr("user-agent", httppkg.UserAgentFromCtx),
+//		slogctx.Attr("path", httppkg.PathFromCtx),
+//	)
+//
+// Use [Limit] to cut values, and limit the total size of the attributes,
+// so that oversized values do not cause records to be dropped.
+//
+//	g := slogctx.Limit(slogctx.LimitOptions{MaxValueLen: 256, MaxTotalLen: 4096},
+//		slogctx.Attr("claims", authpkg.ClaimsFromCtx),
+//	)
+//
 // Use [AttrGetterFunc] to customize the display of types that are not
 // automatically recognized by [Attr] (e.g. user defined type).
 //
@@ -81,4 +111,50 @@
 //				// ...
 //			}
 //		})
+//
+// Use [Pseudonymize] to log a keyed hash of an identifier instead of the
+// identifier itself.
+//
+//	g := slogctx.Pseudonymize(userGetter, slogctx.PseudonymKey{ID: "k1", Secret: secret})
+//
+// Use [Extract], or [Handler.ContextAttrs], to get the context attributes
+// for use outside of logging, and [AttrsToMap], [AttrsToHeader],
+// [AttrsToLabels] or [AttrsToJSON] to convert them.
+//
+//	labels := slogctx.AttrsToLabels(slogctx.Extract(ctx, getters...))
+//
+// Use [Start] to give structure to the records of a request, like a span of
+// a trace. The [Operation] getter adds the path of the nested operations.
+//
+//	ctx, end := slogctx.Start(ctx, "charge_card")
+//	defer func() { end(err) }()
+//
+// Use a [BufferHandler] to log debug records for a request only if it
+// fails.
+//
+//	h = slogctx.NewBufferHandler(h, nil)
+//	// For each request:
+//	ctx = slogctx.WithRecordBuffer(ctx)
+//
+// Use [BeginEvent], [AddToEvent] and [Emit] to log one wide record, or
+// canonical log line, summarizing a request.
+//
+// Use [WithSequence] and the [Sequence] getter to number the records of a
+// request, so their order can be recovered, and [WithChildSequence] to
+// number the records of work done in parallel under its own prefix.
+//
+// Use a [Controller] to change the getters used by a [Handler], or disable
+// them by key, without creating a new handler.
+//
+//	ctrl := slogctx.NewController(getters...)
+//	h = slogctx.NewHandler(h, ctrl)
+//	// ...
+//	ctrl.Disable("config")
+//
+// Set [HandlerOptions.KeyStyle] to convert the keys of context attributes to
+// a naming convention such as snake_case. The semconv package provides
+// getters using the OpenTelemetry semantic convention names, and the ecs
+// package provides getters and a handler for the Elastic Common Schema,
+// which sets [HandlerOptions.Layout] to arrange the context attributes as
+// the schema expects.
 package slogctx
@@ -0,0 +1,38 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+)
+
+// appendDoneAttrs appends a "ctx" group describing why ctx is done, if it
+// is, to dst. See [HandlerOptions.AnnotateDone].
+func appendDoneAttrs(ctx context.Context, dst []slog.Attr) []slog.Attr {
+	err := ctx.Err()
+	if err == nil {
+		return dst
+	}
+
+	attrs := make([]slog.Attr, 2, 3)
+	attrs[0] = slog.Any("err", err)
+	attrs[1] = slog.Any("cause", context.Cause(ctx))
+	if deadline, ok := ctx.Deadline(); ok {
+		attrs = append(attrs, slog.Duration("deadline_remaining", deadline.Sub(clockNow(ctx))))
+	}
+
+	return append(dst, slog.Attr{Key: "ctx", Value: slog.GroupValue(attrs...)})
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Annotating records logged with a done context", func() {
+	var (
+		spyHandler *HandlerSpy
+		handler    *slogctx.Handler
+	)
+
+	BeforeEach(func() {
+		spyHandler = NewHandlerSpy()
+		handler = slogctx.NewHandlerWithOptions(spyHandler,
+			&slogctx.HandlerOptions{AnnotateDone: true},
+			noopGetter,
+		)
+	})
+
+	handle := func(ctx context.Context) []slog.Attr {
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+		Expect(handler.Handle(ctx, rec)).To(Succeed())
+		return GetAttrs(spyHandler.HandleSpy.Rec)
+	}
+
+	It("adds the error and cause of a cancelled context", func() {
+		cause := errors.New("client went away")
+		ctx, cancel := context.WithCancelCause(context.Background())
+		cancel(cause)
+
+		Expect(handle(ctx)).To(Equal([]slog.Attr{
+			slog.Group("ctx",
+				slog.Any("err", context.Canceled),
+				slog.Any("cause", cause),
+			),
+		}))
+	})
+
+	It("adds the time remaining until the deadline", func() {
+		now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx := slogctx.WithClock(context.Background(), func() time.Time {
+			return now
+		})
+		ctx, cancel := context.WithDeadline(ctx, now.Add(-time.Second))
+		defer cancel()
+
+		Expect(handle(ctx)).To(Equal([]slog.Attr{
+			slog.Group("ctx",
+				slog.Any("err", context.DeadlineExceeded),
+				slog.Any("cause", context.DeadlineExceeded),
+				slog.Duration("deadline_remaining", -time.Second),
+			),
+		}))
+	})
+
+	When("the context is not done", func() {
+
+		It("adds nothing", func() {
+			Expect(handle(context.Background())).To(BeEmpty())
+		})
+	})
+})
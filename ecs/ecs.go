@@ -0,0 +1,104 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ecs provides [slogctx.AttrGetter] instances, and a
+// [log/slog.Handler], that produce records in the layout of the Elastic
+// Common Schema (ECS).
+//
+//	h := ecs.NewHandler(
+//		slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+//			ReplaceAttr: ecs.ReplaceAttr,
+//		}),
+//		ecs.TraceID(tracepkg.TraceIDFromCtx),
+//		semconv.UserID(authpkg.UserIDFromCtx),
+//	)
+//
+// See https://www.elastic.co/guide/en/ecs/current/ecs-field-reference.html
+// for the definition of each field.
+package ecs
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx"
+)
+
+// ECS field names. Fields that ECS shares with the OpenTelemetry semantic
+// conventions, such as "http.request.method", "url.path", "user.id" and
+// "service.name", are provided by the semconv package, whose getters can be
+// used with a [Handler].
+const (
+	ClientIPKey      = "client.ip"
+	EventDatasetKey  = "event.dataset"
+	LogLevelKey      = "log.level"
+	LogLoggerKey     = "log.logger"
+	MessageKey       = "message"
+	SpanIDKey        = "span.id"
+	TimestampKey     = "@timestamp"
+	TraceIDKey       = "trace.id"
+	TransactionIDKey = "transaction.id"
+)
+
+// ClientIP returns a getter for the client.ip field.
+func ClientIP(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(ClientIPKey, lookup)
+}
+
+// SpanID returns a getter for the span.id field.
+func SpanID(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(SpanIDKey, lookup)
+}
+
+// TraceID returns a getter for the trace.id field.
+func TraceID(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(TraceIDKey, lookup)
+}
+
+// TransactionID returns a getter for the transaction.id field.
+func TransactionID(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(TransactionIDKey, lookup)
+}
+
+// EventDataset returns a getter that always returns the event.dataset field
+// with the dataset.
+//
+// Panics if dataset is empty.
+func EventDataset(dataset string) slogctx.AttrGetter {
+	if len(dataset) == 0 {
+		panic("dataset is empty")
+	}
+
+	return slogctx.Attr(EventDatasetKey, func(context.Context) (string, bool) {
+		return dataset, true
+	})
+}
+
+// ReplaceAttr renames the built-in attributes of a record to their ECS
+// field names, for use as [log/slog.HandlerOptions.ReplaceAttr].
+func ReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+
+	switch a.Key {
+	case slog.TimeKey:
+		a.Key = TimestampKey
+	case slog.LevelKey:
+		a.Key = LogLevelKey
+	case slog.MessageKey:
+		a.Key = MessageKey
+	}
+	return a
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/pfflabs/slogctx"
+)
+
+// Handler wraps a target [log/slog.Handler] and adds attributes taken from
+// a [context.Context] in the ECS layout, where a dotted key is an object
+// path, e.g. "trace.id" is the "id" field of the "trace" object.
+//
+// Attributes from the context are always added at the top level of the
+// record, where ECS expects them, even after [Handler.WithGroup]. Attributes
+// with a common path, including those in groups created by [slogctx.Group],
+// are merged into one object. The names of the groups opened by
+// [Handler.WithGroup] are joined with a '.' and added as the log.logger
+// field, unless a getter provides it.
+type Handler struct {
+	handler *slogctx.Handler
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler returns a new Handler that will add attributes taken from the
+// provided context, in the ECS layout, then delegates handling to the
+// target.
+//
+// Panics if target handler is nil, receives zero [slogctx.AttrGetter]
+// instances, or any [slogctx.AttrGetter] references are nil.
+func NewHandler(target slog.Handler, attrGetters ...slogctx.AttrGetter) *Handler {
+	return &Handler{
+		handler: slogctx.NewHandlerWithOptions(target,
+			&slogctx.HandlerOptions{Layout: layout{}},
+			attrGetters...,
+		),
+	}
+}
+
+// Enabled returns whether the target handler is enabled for the context and
+// level.
+func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle delegates handling the record, with the attributes gathered from
+// the context, to the target handler.
+func (h *Handler) Handle(ctx context.Context, rec slog.Record) error {
+	return h.handler.Handle(ctx, rec)
+}
+
+// WithAttrs returns a handler that will include the given attributes when
+// handling records.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &Handler{handler: h.handler.WithAttrs(attrs).(*slogctx.Handler)}
+}
+
+// WithGroup returns a handler that will group the record's attributes, but
+// not those from the context, when handling records. If the name is empty,
+// WithGroup returns the receiver.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if len(name) == 0 {
+		return h
+	}
+	return &Handler{handler: h.handler.WithGroup(name).(*slogctx.Handler)}
+}
+
+// layout arranges the context attributes in the ECS layout, adding the
+// log.logger field for the groups opened by WithGroup.
+type layout struct{}
+
+func (layout) LayoutAttrs(component string, attrs []slog.Attr) []slog.Attr {
+	if len(component) > 0 && !hasPath(attrs, LogLoggerKey) {
+		attrs = append(slices.Clip(attrs), slog.String(LogLoggerKey, component))
+	}
+	return Layout(attrs)
+}
+
+// hasPath returns whether attrs, including those in groups, have an
+// attribute with the dotted path.
+func hasPath(attrs []slog.Attr, path string) bool {
+	for _, a := range attrs {
+		switch {
+		case a.Key == path:
+			return true
+		case a.Value.Kind() == slog.KindGroup:
+			rest := path
+			if len(a.Key) > 0 {
+				var ok bool
+				if rest, ok = strings.CutPrefix(path, a.Key+"."); !ok {
+					continue
+				}
+			}
+			if hasPath(a.Value.Group(), rest) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx"
+	"github.com/pfflabs/slogctx/ecs"
+	"github.com/pfflabs/slogctx/semconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func lookup[T any](v T) func(context.Context) (T, bool) {
+	return func(context.Context) (T, bool) {
+		return v, true
+	}
+}
+
+var _ = Describe("Logging in the ECS layout", func() {
+	var (
+		buf    *bytes.Buffer
+		logger *slog.Logger
+	)
+
+	BeforeEach(func() {
+		buf = &bytes.Buffer{}
+		target := slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			ReplaceAttr: ecs.ReplaceAttr,
+		})
+		logger = slog.New(ecs.NewHandler(target,
+			ecs.TraceID(lookup("t1")),
+			semconv.HTTPRequestMethod(lookup("GET")),
+			slogctx.Group("http", slogctx.Attr("response.status_code", lookup(200))),
+		))
+	})
+
+	output := func() map[string]any {
+		var m map[string]any
+		Expect(json.Unmarshal(buf.Bytes(), &m)).To(Succeed())
+		delete(m, ecs.TimestampKey)
+		return m
+	}
+
+	It("nests the context attributes and renames the built-in attributes", func() {
+		logger.InfoContext(context.Background(), "hello", "a", 1)
+
+		Expect(output()).To(Equal(map[string]any{
+			"log.level": "INFO",
+			"message":   "hello",
+			"trace":     map[string]any{"id": "t1"},
+			"http": map[string]any{
+				"request":  map[string]any{"method": "GET"},
+				"response": map[string]any{"status_code": 200.0},
+			},
+			"a": 1.0,
+		}))
+	})
+
+	When("groups are opened", func() {
+
+		It("keeps the context attributes at the top level and sets log.logger", func() {
+			logger.WithGroup("db").With("b", 2).WithGroup("pool").
+				InfoContext(context.Background(), "hello", "a", 1)
+
+			Expect(output()).To(Equal(map[string]any{
+				"log.level": "INFO",
+				"message":   "hello",
+				"trace":     map[string]any{"id": "t1"},
+				"http": map[string]any{
+					"request":  map[string]any{"method": "GET"},
+					"response": map[string]any{"status_code": 200.0},
+				},
+				"log": map[string]any{"logger": "db.pool"},
+				"db": map[string]any{
+					"b":    2.0,
+					"pool": map[string]any{"a": 1.0},
+				},
+			}))
+		})
+	})
+
+	When("no getters are provided", func() {
+
+		It("panics", func() {
+			Expect(func() { ecs.NewHandler(slog.Default().Handler()) }).
+				To(PanicWith("received 0 AttrGetters"))
+		})
+	})
+})
+
+var _ = Describe("Laying out attributes", func() {
+
+	It("merges objects and uses the last value of a field", func() {
+		Expect(ecs.Layout([]slog.Attr{
+			slog.String("user.id", "u1"),
+			slog.Group("user", slog.String("name", "n")),
+			slog.String("user.id", "u2"),
+		})).To(Equal([]slog.Attr{
+			slog.Group("user", slog.String("id", "u2"), slog.String("name", "n")),
+		}))
+	})
+})
@@ -0,0 +1,110 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ecs
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Layout returns the attributes in the ECS layout. Each dotted key is
+// expanded into nested groups, and groups with the same key are merged, in
+// the order their keys first appear. For example
+//
+//	slog.String("trace.id", "t1")
+//	slog.Group("http", slog.String("request.method", "GET"))
+//	slog.String("http.response.status_code", "200")
+//
+// becomes
+//
+//	slog.Group("trace", slog.String("id", "t1"))
+//	slog.Group("http",
+//		slog.Group("request", slog.String("method", "GET")),
+//		slog.Group("response", slog.String("status_code", "200")),
+//	)
+//
+// If a field is set more than once, the last value is used.
+func Layout(attrs []slog.Attr) []slog.Attr {
+	var root node
+	for _, a := range attrs {
+		root.add(nil, a)
+	}
+	return root.attrs()
+}
+
+// node is a field, or an object if it has children.
+type node struct {
+	key      string
+	value    slog.Value
+	children []*node
+}
+
+func (n *node) add(path []string, a slog.Attr) {
+	if len(a.Key) > 0 {
+		path = append(path, strings.Split(a.Key, ".")...)
+	}
+
+	v := a.Value.Resolve()
+	if v.Kind() == slog.KindGroup {
+		for _, ga := range v.Group() {
+			n.add(path, ga)
+		}
+		return
+	}
+
+	if len(a.Key) == 0 {
+		return
+	}
+	n.set(path, v)
+}
+
+func (n *node) set(path []string, v slog.Value) {
+	child := n.child(path[0])
+	if len(path) == 1 {
+		child.value = v
+		child.children = nil
+		return
+	}
+
+	child.value = slog.Value{}
+	child.set(path[1:], v)
+}
+
+func (n *node) child(key string) *node {
+	for _, c := range n.children {
+		if c.key == key {
+			return c
+		}
+	}
+
+	c := &node{key: key}
+	n.children = append(n.children, c)
+	return c
+}
+
+func (n *node) attrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(n.children))
+	for _, c := range n.children {
+		if len(c.children) > 0 {
+			attrs = append(attrs, slog.Attr{
+				Key:   c.key,
+				Value: slog.GroupValue(c.attrs()...),
+			})
+		} else {
+			attrs = append(attrs, slog.Attr{Key: c.key, Value: c.value})
+		}
+	}
+	return attrs
+}
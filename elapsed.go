@@ -0,0 +1,105 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type (
+	clockCtxKey struct{}
+
+	markCtxKey struct {
+		name string
+	}
+)
+
+// WithClock returns a copy of ctx that uses now, instead of [time.Now], to
+// tell the time for [MarkStart], [Mark], [Elapsed] and [ElapsedSince], e.g.
+// so that tests are deterministic.
+//
+// Panics if now is nil.
+func WithClock(ctx context.Context, now func() time.Time) context.Context {
+	if now == nil {
+		panic("now is nil")
+	}
+	return context.WithValue(ctx, clockCtxKey{}, now)
+}
+
+// clockNow returns the time using the clock in ctx, if any.
+func clockNow(ctx context.Context) time.Time {
+	if now, ok := ctx.Value(clockCtxKey{}).(func() time.Time); ok {
+		return now()
+	}
+	return time.Now()
+}
+
+// MarkStart returns a copy of ctx marked with the current time, from which
+// [Elapsed] measures. It is the same as Mark with an empty name.
+func MarkStart(ctx context.Context) context.Context {
+	return Mark(ctx, "")
+}
+
+// Mark returns a copy of ctx with a named mark of the current time, e.g.
+// "db.tx" for the start of a database transaction, from which
+// [ElapsedSince] measures. Marking a name again in a derived context moves
+// the mark for that context.
+func Mark(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, markCtxKey{name: name}, clockNow(ctx))
+}
+
+type elapsedAttrGetter struct {
+	key  string
+	mark markCtxKey
+}
+
+// Elapsed returns an [AttrGetter] that adds the time since the mark made by
+// [MarkStart] as a duration.
+//
+// Panics if key is empty.
+func Elapsed(key string) AttrGetter {
+	return ElapsedSince(key, "")
+}
+
+// ElapsedSince returns an [AttrGetter] that adds the time since the named
+// mark made by [Mark] as a duration.
+//
+// Panics if key is empty.
+func ElapsedSince(key, name string) AttrGetter {
+	validateKey(key)
+	return &elapsedAttrGetter{key: key, mark: markCtxKey{name: name}}
+}
+
+func (e *elapsedAttrGetter) attrKey() string {
+	return e.key
+}
+
+func (e *elapsedAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return e.AppendAttrs(ctx, nil)
+}
+
+func (e *elapsedAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	start, ok := ctx.Value(e.mark).(time.Time)
+	if !ok {
+		return dst
+	}
+
+	return append(dst, slog.Duration(e.key, clockNow(ctx).Sub(start)))
+}
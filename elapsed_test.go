@@ -0,0 +1,81 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Getting the time elapsed since a mark", func() {
+	var (
+		now time.Time
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx = slogctx.WithClock(context.Background(), func() time.Time {
+			return now
+		})
+	})
+
+	It("adds the time since the start", func() {
+		ctx = slogctx.MarkStart(ctx)
+		now = now.Add(2 * time.Second)
+
+		Expect(slogctx.Elapsed("elapsed").GetAttrs(ctx)).To(Equal(
+			[]slog.Attr{slog.Duration("elapsed", 2*time.Second)},
+		))
+	})
+
+	It("adds the time since a named mark", func() {
+		ctx = slogctx.MarkStart(ctx)
+		now = now.Add(time.Second)
+		ctx = slogctx.Mark(ctx, "db.tx")
+		now = now.Add(time.Second)
+
+		getter := slogctx.Group("elapsed",
+			slogctx.Elapsed("request"),
+			slogctx.ElapsedSince("db_tx", "db.tx"),
+		)
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.Group("elapsed",
+				slog.Duration("request", 2*time.Second),
+				slog.Duration("db_tx", time.Second),
+			),
+		}))
+	})
+
+	When("the context has no mark", func() {
+
+		It("returns no attributes", func() {
+			Expect(slogctx.Elapsed("elapsed").GetAttrs(ctx)).To(BeEmpty())
+		})
+	})
+
+	When("the clock is nil", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.WithClock(ctx, nil) }).To(PanicWith("now is nil"))
+		})
+	})
+})
@@ -0,0 +1,191 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	eventCtxKey struct{}
+
+	// eventRecordCtxKey marks the context of the record logged by Emit.
+	eventRecordCtxKey struct{}
+)
+
+// eventsBegun is set once BeginEvent is first called, so that handlers do
+// not look for events in the contexts of records until they are used.
+var eventsBegun atomic.Bool
+
+// event is a wide event begun by BeginEvent.
+type event struct {
+	name  string
+	start time.Time
+
+	mu      sync.Mutex
+	attrs   []slog.Attr
+	counts  map[slog.Level]int
+	emitted bool
+}
+
+// BeginEvent returns a copy of ctx with a new wide event, also known as a
+// canonical log line, e.g. for a request. Attributes are added to the
+// event with [AddToEvent] and it is logged as a single record by [Emit].
+//
+//	ctx = slogctx.BeginEvent(ctx, "http_request")
+//	defer slogctx.Emit(ctx)
+//	// ...
+//	slogctx.AddToEvent(ctx, slog.Int("rows", n))
+//
+// Panics if name is empty.
+func BeginEvent(ctx context.Context, name string) context.Context {
+	if len(name) == 0 {
+		panic("name is empty")
+	}
+
+	eventsBegun.Store(true)
+	return context.WithValue(ctx, eventCtxKey{}, &event{
+		name:   name,
+		start:  clockNow(ctx),
+		counts: map[slog.Level]int{},
+	})
+}
+
+// AddToEvent adds the attributes to the event of ctx, if any. An attribute
+// with the key of one already added replaces its value, keeping its place.
+// It is safe for concurrent use. Attributes added after the event is
+// emitted are ignored.
+func AddToEvent(ctx context.Context, attrs ...slog.Attr) {
+	ev, ok := ctx.Value(eventCtxKey{}).(*event)
+	if !ok {
+		return
+	}
+
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	if ev.emitted {
+		return
+	}
+	for _, a := range attrs {
+		i := slices.IndexFunc(ev.attrs, func(b slog.Attr) bool {
+			return b.Key == a.Key
+		})
+		if i < 0 {
+			ev.attrs = append(ev.attrs, a)
+		} else {
+			ev.attrs[i] = a
+		}
+	}
+}
+
+// countEventRecord counts a record logged with ctx towards its event, if
+// any.
+func countEventRecord(ctx context.Context, level slog.Level) {
+	if !eventsBegun.Load() {
+		return
+	}
+
+	ev, ok := ctx.Value(eventCtxKey{}).(*event)
+	if !ok {
+		return
+	}
+
+	ev.mu.Lock()
+	defer ev.mu.Unlock()
+	if !ev.emitted {
+		ev.counts[level]++
+	}
+}
+
+// Emit logs the event of ctx, if any, with [log/slog.Default]. See
+// [EmitLogger].
+func Emit(ctx context.Context) {
+	emit(ctx, slog.Default())
+}
+
+// EmitLogger logs the event of ctx, if any, with the logger as a single
+// record at [log/slog.LevelInfo] with the event's name as its message. The
+// record holds the attributes added by [AddToEvent], "duration" holding the
+// time since the event began, and "records" holding a group of the number
+// of records logged with the event's context by a [Handler], by level, e.g.
+// "records.WARN=2". A record passed through more than one Handler is
+// counted once. The record is logged with ctx, so a [Handler] also adds the
+// attributes of its getters, all of them even if [HandlerOptions.EmitOnce]
+// is set. Only the first call logs a record.
+//
+// Panics if logger is nil.
+func EmitLogger(ctx context.Context, logger *slog.Logger) {
+	if logger == nil {
+		panic("logger is nil")
+	}
+	emit(ctx, logger)
+}
+
+// emit logs the event with the caller of its caller as the record's
+// source.
+func emit(ctx context.Context, logger *slog.Logger) {
+	ev, ok := ctx.Value(eventCtxKey{}).(*event)
+	if !ok {
+		return
+	}
+
+	ev.mu.Lock()
+	if ev.emitted {
+		ev.mu.Unlock()
+		return
+	}
+	ev.emitted = true
+	attrs, counts := ev.attrs, ev.counts
+	ev.mu.Unlock()
+
+	if !logger.Enabled(ctx, slog.LevelInfo) {
+		return
+	}
+
+	levels := make([]slog.Level, 0, len(counts))
+	for level := range counts {
+		levels = append(levels, level)
+	}
+	slices.Sort(levels)
+	countAttrs := make([]slog.Attr, len(levels))
+	for i, level := range levels {
+		countAttrs[i] = slog.Int(level.String(), counts[level])
+	}
+
+	var pcs [1]uintptr
+	// Skip runtime.Callers, emit and Emit or EmitLogger.
+	runtime.Callers(3, pcs[:])
+
+	now := clockNow(ctx)
+	rec := slog.NewRecord(now, slog.LevelInfo, ev.name, pcs[0])
+	rec.AddAttrs(attrs...)
+	rec.AddAttrs(
+		slog.Duration("duration", now.Sub(ev.start)),
+		slog.Attr{Key: "records", Value: slog.GroupValue(countAttrs...)},
+	)
+	ctx = context.WithValue(ctx, eventRecordCtxKey{}, struct{}{})
+	_ = logger.Handler().Handle(ctx, rec)
+}
+
+// isEventRecord returns whether ctx is that of a record logged by Emit.
+func isEventRecord(ctx context.Context) bool {
+	return ctx.Value(eventRecordCtxKey{}) != nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emitting wide events", func() {
+	var (
+		target *recordsHandler
+		logger *slog.Logger
+		now    time.Time
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		target = &recordsHandler{}
+		logger = slog.New(slogctx.NewHandler(target, barGetter))
+
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx = slogctx.WithClock(context.Background(), func() time.Time {
+			return now
+		})
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+	})
+
+	It("logs one record with the added attributes, counts and duration", func() {
+		ctx := slogctx.BeginEvent(ctx, "http_request")
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				slogctx.AddToEvent(ctx, slog.Bool("added", true))
+			}()
+		}
+		wg.Wait()
+
+		logger.InfoContext(ctx, "a")
+		logger.WarnContext(ctx, "b")
+		logger.WarnContext(ctx, "c")
+		now = now.Add(time.Second)
+
+		slogctx.EmitLogger(ctx, logger)
+		slogctx.EmitLogger(ctx, logger)
+
+		recs := target.records()
+		Expect(recs).To(HaveLen(4))
+		rec := recs[3]
+		Expect(rec.Message).To(Equal("http_request"))
+
+		attrs := GetAttrs(rec)
+		Expect(attrs).To(Equal([]slog.Attr{
+			slog.Bool("added", true),
+			slog.Duration("duration", time.Second),
+			slog.Group("records", slog.Int("INFO", 1), slog.Int("WARN", 2)),
+			barAttr,
+		}))
+
+		frame, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+		Expect(frame.File).To(HaveSuffix("event_test.go"))
+	})
+
+	It("keeps the last value added with a key in its first place", func() {
+		ctx := slogctx.BeginEvent(ctx, "http_request")
+		slogctx.AddToEvent(ctx, slog.Int("status", 200), slog.String("route", "/"))
+		slogctx.AddToEvent(ctx, slog.Int("status", 500))
+		slogctx.EmitLogger(ctx, logger)
+
+		Expect(GetAttrs(target.records()[0])[:2]).To(Equal([]slog.Attr{
+			slog.Int("status", 500),
+			slog.String("route", "/"),
+		}))
+	})
+
+	It("counts a record once when it passes through more than one handler", func() {
+		ctx := slogctx.BeginEvent(ctx, "http_request")
+		logger := slog.New(slogctx.NewHandlerWithOptions(
+			logger.Handler(),
+			&slogctx.HandlerOptions{AnnotateDone: true},
+			fooGetter,
+		))
+		logger.InfoContext(ctx, "a")
+		slogctx.EmitLogger(ctx, logger)
+
+		Expect(GetAttrs(target.records()[1])).To(ContainElement(
+			slog.Group("records", slog.Int("INFO", 1)),
+		))
+	})
+
+	When("the handler emits context attributes once", func() {
+
+		It("adds all the context attributes to the event record", func() {
+			ctx := slogctx.BeginEvent(slogctx.WithEmitOnce(ctx), "http_request")
+			logger := slog.New(slogctx.NewHandlerWithOptions(target,
+				&slogctx.HandlerOptions{EmitOnce: true},
+				barGetter,
+			))
+			logger.InfoContext(ctx, "a")
+			slogctx.EmitLogger(ctx, logger)
+
+			Expect(GetAttrs(target.records()[1])).To(ContainElement(barAttr))
+		})
+	})
+
+	When("the context has no event", func() {
+
+		It("logs nothing", func() {
+			slogctx.AddToEvent(ctx, slog.Bool("added", true))
+			slogctx.EmitLogger(ctx, logger)
+
+			Expect(target.records()).To(BeEmpty())
+		})
+	})
+
+	When("the name is empty", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.BeginEvent(ctx, "") }).To(PanicWith("name is empty"))
+		})
+	})
+})
@@ -0,0 +1,110 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Extract returns the attributes one or more [AttrGetter] instances take
+// from a [context.Context], for use outside of logging, e.g. in error
+// responses or metric labels.
+//
+// Panics if receives zero [AttrGetter] instances, or any [AttrGetter]
+// references are nil.
+func Extract(ctx context.Context, attrGetters ...AttrGetter) []slog.Attr {
+	return concat(attrGetters).GetAttrs(ctx)
+}
+
+// ContextAttrs returns the attributes the handler would add to a record
+// logged with the context, with [HandlerOptions.KeyStyle] applied. Groups
+// opened by [Handler.WithGroup] are not included.
+func (h *Handler) ContextAttrs(ctx context.Context) []slog.Attr {
+	attrs := appendAttrs(ctx, h.attrGetter, nil)
+	if h.opts.KeyStyle != KeyAsIs {
+		restyleKeys(h.opts.KeyStyle, attrs)
+	}
+	return attrs
+}
+
+// AttrsToMap returns the attributes as a map of keys to formatted values.
+// Groups are flattened, joining the keys of a group and its attributes with
+// a '.', e.g. "config.hostname".
+func AttrsToMap(attrs []slog.Attr) map[string]string {
+	flat := appendFlatAttrs(nil, "", ".", attrs)
+	m := make(map[string]string, len(flat))
+	for _, a := range flat {
+		if len(a.Key) > 0 {
+			m[a.Key] = a.Value.String()
+		}
+	}
+	return m
+}
+
+// AttrsToHeader returns the attributes as HTTP header fields, e.g. for
+// passing them on to another service. Groups are flattened as by
+// [AttrsToMap], and the name of each field is the prefix followed by the
+// key, with '.' and '_' replaced by '-'. For example, with the prefix
+// "X-Ctx-", the key "user.id" is the field "X-Ctx-User-Id".
+//
+// Control characters in values, which are not allowed in fields, are
+// escaped as by [Sanitize].
+func AttrsToHeader(attrs []slog.Attr, prefix string) http.Header {
+	m := AttrsToMap(attrs)
+	header := make(http.Header, len(m))
+	for k, v := range m {
+		header.Set(headerKeyReplacer.Replace(prefix+k), sanitizeString(v))
+	}
+	return header
+}
+
+var headerKeyReplacer = strings.NewReplacer(".", "-", "_", "-")
+
+// AttrsToLabels returns the attributes as metric labels. Groups are
+// flattened as by [AttrsToMap], and characters of keys that are not valid in
+// Prometheus label names, which match [a-zA-Z_][a-zA-Z0-9_]*, are replaced
+// by '_', e.g. "user.id" is the label "user_id".
+func AttrsToLabels(attrs []slog.Attr) map[string]string {
+	m := AttrsToMap(attrs)
+	labels := make(map[string]string, len(m))
+	for k, v := range m {
+		labels[labelName(k)] = v
+	}
+	return labels
+}
+
+func labelName(key string) string {
+	var b strings.Builder
+	b.Grow(len(key))
+	for i, r := range key {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '_':
+		case r >= '0' && r <= '9' && i > 0:
+		default:
+			r = '_'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// AttrsToJSON returns the attributes encoded as a JSON object in the same
+// way as a [log/slog.JSONHandler], with groups as nested objects.
+func AttrsToJSON(attrs []slog.Attr) []byte {
+	return []byte(encodeJSON(attrs))
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Extracting context attributes", func() {
+	var ctx context.Context
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+	})
+
+	It("returns the getters' attributes", func() {
+		Expect(slogctx.Extract(ctx, fooGetter, barGetter)).To(Equal(
+			[]slog.Attr{fooAttr, barAttr},
+		))
+	})
+
+	It("returns the attributes a handler would add", func() {
+		h := slogctx.NewHandlerWithOptions(NewHandlerSpy(),
+			&slogctx.HandlerOptions{KeyStyle: slogctx.KeyKebabCase},
+			slogctx.Attr("userID", func(context.Context) (string, bool) {
+				return "u1", true
+			}),
+		)
+		Expect(h.ContextAttrs(ctx)).To(Equal(
+			[]slog.Attr{slog.String("user-id", "u1")},
+		))
+	})
+
+	Describe("converting the attributes", func() {
+		attrs := []slog.Attr{
+			slog.Group("user", slog.String("id", "u1"), slog.Int("age", 7)),
+			slog.String("request_id", "a\nb"),
+		}
+
+		It("converts them to a map", func() {
+			Expect(slogctx.AttrsToMap(attrs)).To(Equal(map[string]string{
+				"user.id":    "u1",
+				"user.age":   "7",
+				"request_id": "a\nb",
+			}))
+		})
+
+		It("converts them to HTTP header fields", func() {
+			Expect(slogctx.AttrsToHeader(attrs, "X-Ctx-")).To(Equal(http.Header{
+				"X-Ctx-User-Id":    {"u1"},
+				"X-Ctx-User-Age":   {"7"},
+				"X-Ctx-Request-Id": {`a\nb`},
+			}))
+		})
+
+		It("converts them to metric labels", func() {
+			Expect(slogctx.AttrsToLabels(attrs)).To(Equal(map[string]string{
+				"user_id":    "u1",
+				"user_age":   "7",
+				"request_id": "a\nb",
+			}))
+		})
+
+		It("converts them to a JSON object", func() {
+			Expect(string(slogctx.AttrsToJSON(attrs))).To(Equal(
+				`{"user":{"id":"u1","age":7},"request_id":"a\nb"}`,
+			))
+		})
+	})
+})
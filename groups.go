@@ -0,0 +1,149 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"slices"
+)
+
+// GroupStyle is how a [Handler] passes groups to its target handler.
+type GroupStyle int
+
+const (
+	// GroupNested passes groups, including those opened by
+	// [Handler.WithGroup], to the target handler unchanged.
+	GroupNested GroupStyle = iota
+
+	// GroupFlatten replaces groups with attributes whose keys are the keys
+	// of the groups and attribute joined by [HandlerOptions.GroupSeparator],
+	// e.g. "config.hostname". Groups opened by [Handler.WithGroup], and
+	// those in the record and passed to [Handler.WithAttrs], are flattened
+	// too, so the target handler never receives a group.
+	GroupFlatten
+
+	// GroupJSON flattens groups like GroupFlatten except for the context
+	// attributes, which are encoded as a JSON object in a single string
+	// attribute with the key [HandlerOptions.ContextKey].
+	GroupJSON
+)
+
+// flatRecord returns a copy of rec, including the context attributes, with
+// its groups flattened.
+func (h *Handler) flatRecord(rec slog.Record, attrs []slog.Attr) slog.Record {
+	sep := h.opts.GroupSeparator
+	flat := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		flat.AddAttrs(appendFlatAttr(nil, h.prefix, sep, a)...)
+		return true
+	})
+
+	if len(attrs) == 0 {
+		return flat
+	}
+
+	if h.opts.GroupStyle == GroupJSON {
+		flat.AddAttrs(slog.String(h.prefix+h.opts.ContextKey, encodeJSON(attrs)))
+	} else {
+		flat.AddAttrs(appendFlatAttrs(nil, h.prefix, sep, attrs)...)
+	}
+
+	return flat
+}
+
+// handlerGroup is a group opened by [Handler.WithGroup], and the
+// attributes added to it, that the handler nests itself.
+type handlerGroup struct {
+	name  string
+	attrs []slog.Attr
+}
+
+// groupedRecord returns a copy of rec with the top attributes followed by
+// its attributes, and the nested attributes, nested in the handler's
+// groups.
+func (h *Handler) groupedRecord(rec slog.Record, top, nested []slog.Attr) slog.Record {
+	content := make([]slog.Attr, 0, rec.NumAttrs()+len(nested))
+	rec.Attrs(func(a slog.Attr) bool {
+		content = append(content, a)
+		return true
+	})
+	content = append(content, nested...)
+
+	for i := len(h.groups) - 1; i >= 0; i-- {
+		g := h.groups[i]
+		groupAttrs := append(slices.Clip(g.attrs), content...)
+		content = []slog.Attr{{Key: g.name, Value: slog.GroupValue(groupAttrs...)}}
+	}
+
+	grouped := slog.NewRecord(rec.Time, rec.Level, rec.Message, rec.PC)
+	grouped.AddAttrs(top...)
+	grouped.AddAttrs(content...)
+	return grouped
+}
+
+func appendFlatAttrs(dst []slog.Attr, prefix, sep string, attrs []slog.Attr) []slog.Attr {
+	for _, a := range attrs {
+		dst = appendFlatAttr(dst, prefix, sep, a)
+	}
+	return dst
+}
+
+// appendFlatAttr appends a, or the attributes of a's group, to dst with
+// their keys prefixed. As with log/slog's handlers, the attributes of a
+// group with an empty key are not prefixed with the group's key, and, as
+// log/slog's handlers ignore them, other attributes with an empty key are
+// dropped.
+func appendFlatAttr(dst []slog.Attr, prefix, sep string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() != slog.KindGroup {
+		if len(a.Key) == 0 {
+			return dst
+		}
+		a.Key = prefix + a.Key
+		return append(dst, a)
+	}
+
+	if len(a.Key) > 0 {
+		prefix = prefix + a.Key + sep
+	}
+	return appendFlatAttrs(dst, prefix, sep, a.Value.Group())
+}
+
+// jsonOmitKeys removes the built-in attributes from the output of the
+// handler used by encodeJSON.
+func jsonOmitKeys(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) == 0 {
+		switch a.Key {
+		case slog.TimeKey, slog.LevelKey, slog.MessageKey:
+			return slog.Attr{}
+		}
+	}
+	return a
+}
+
+// encodeJSON returns attrs encoded as a JSON object in the same way as a
+// [log/slog.JSONHandler].
+func encodeJSON(attrs []slog.Attr) string {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{ReplaceAttr: jsonOmitKeys})
+
+	var rec slog.Record
+	rec.AddAttrs(attrs...)
+	_ = h.Handle(context.Background(), rec)
+
+	return string(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
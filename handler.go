@@ -17,6 +17,8 @@ package slogctx
 import (
 	"context"
 	"log/slog"
+	"slices"
+	"sync"
 )
 
 // Handler wraps a target [log/slog.Handler] and extracts attributes from
@@ -25,59 +27,371 @@ import (
 type Handler struct {
 	attrGetter AttrGetter
 	target     slog.Handler
+	opts       HandlerOptions
+
+	// component is the '.' separated names of the groups opened by
+	// WithGroup.
+	component string
+
+	// componentLevel caches the override for the component when Levels is
+	// set.
+	componentLevel *componentLevel
+
+	// prefix is the names of the groups opened by WithGroup, each followed
+	// by the group separator, when the groups are not passed to the target.
+	prefix string
+
+	// onceOwner identifies the handler's state in an emit once scope when
+	// EmitOnce is set.
+	onceOwner *onceOwner
+
+	// countsEvents is whether the handler counts records towards events,
+	// which only the innermost Handler in a chain of handlers does.
+	countsEvents bool
+
+	// groups are the groups opened by WithGroup, and the attributes added
+	// to them, when they are nested by the handler rather than the target
+	// so that attributes can be added at the top level.
+	groups []handlerGroup
+}
+
+// HandlerOptions are options for a [Handler]. A zero HandlerOptions
+// consists entirely of default values.
+type HandlerOptions struct {
+	// Levels, if set, decides which records are handled instead of the
+	// target handler. The level for a handler is that of the component
+	// named by the groups opened by WithGroup, see [Levels.LevelFor]. The
+	// target handler should be configured to handle all levels.
+	Levels *Levels
+
+	// GroupStyle is how groups are passed to the target handler. The
+	// default is [GroupNested].
+	GroupStyle GroupStyle
+
+	// GroupSeparator joins the keys of groups and their attributes when
+	// GroupStyle is not [GroupNested]. If empty, "." is used.
+	GroupSeparator string
+
+	// KeyStyle is the naming convention applied to the keys of the context
+	// attributes. The default is [KeyAsIs].
+	KeyStyle KeyStyle
+
+	// MessageTemplates, if set, fills in the placeholders of a record's
+	// message, such as "{user_id}" in "user {user_id} exceeded quota", with
+	// attributes from the getters and the record, see [FillTemplate].
+	// Placeholders use the getters' keys, before KeyStyle is applied. The
+	// original message is added as a top-level attribute with the key
+	// TemplateKey, even after WithGroup, so groups are not passed to the
+	// target handler, which receives them nested in each record instead.
+	// After WithGroup, the groups and the attributes added by WithAttrs are
+	// built for each record, which allocates, and the target prepares them
+	// for each record rather than once.
+	MessageTemplates bool
+
+	// TemplateKey is the key of the attribute holding the original message
+	// when MessageTemplates is set. If empty, "msg_template" is used.
+	TemplateKey string
+
+	// EmitOnce, if set, passes each context attribute to the target
+	// handler only when its value differs from the one last passed by the
+	// handler, or a handler derived from it, in a scope started by
+	// [WithEmitOnce]. So the first record in the scope carries all the
+	// attributes, later ones only the attribute with the key ReferenceKey,
+	// e.g. a request ID, and those that change, e.g. a sequence number or
+	// a value set in a derived context. Records logged with contexts
+	// outside of a scope always carry all the attributes.
+	//
+	// A record that a target handler drops, such as one buffered and then
+	// thrown away by a [BufferHandler], still counts as passed, so such
+	// handlers should wrap this handler rather than be its target. A
+	// BufferHandler wrapping this handler takes all the context attributes
+	// of a record when it is buffered, and they do not count as passed.
+	EmitOnce bool
+
+	// ReferenceKey is the key, before KeyStyle is applied, of the context
+	// attribute passed with every record when EmitOnce is set. If empty, no
+	// attribute is passed.
+	ReferenceKey string
+
+	// AnnotateDone, if set, adds a "ctx" group to records logged with a
+	// context that is done, holding the context's error as "err", its
+	// cause, see [context.Cause], as "cause", and, if it has a deadline,
+	// the time remaining until the deadline, which is negative once it has
+	// passed, as "deadline_remaining".
+	AnnotateDone bool
+
+	// ContextKey is the key of the attribute holding the context
+	// attributes when GroupStyle is [GroupJSON]. If empty, "context" is
+	// used.
+	ContextKey string
+
+	// Layout, if set, arranges the context attributes, after KeyStyle is
+	// applied, and they are added at the top level of records, even after
+	// WithGroup, so groups are not passed to the target handler, which
+	// receives them nested in each record instead, at the same cost as
+	// with MessageTemplates.
+	Layout AttrLayout
+}
+
+// AttrLayout arranges the context attributes of records for a layout
+// expected by a log consumer, see [HandlerOptions.Layout]. Values of the
+// types implementing it must be comparable.
+type AttrLayout interface {
+	// LayoutAttrs returns the context attributes of a record logged by a
+	// handler for the component, the '.' separated names of the groups
+	// opened by WithGroup. It must not modify or retain attrs.
+	LayoutAttrs(component string, attrs []slog.Attr) []slog.Attr
 }
 
 var _ slog.Handler = (*Handler)(nil)
 
+// attrsPool holds the buffers used to gather attributes from the context
+// while handling a record.
+var attrsPool = sync.Pool{
+	New: func() any {
+		attrs := make([]slog.Attr, 0, 16)
+		return &attrs
+	},
+}
+
 // NewHandler returns a new Handler that will add attributes taken from the
 // provided context then delegates handling to the target.
 //
 // Panics if target handler is nil, receives zero [AttrGetter] instances, or
 // any [AttrGetter] references are nil.
 func NewHandler(target slog.Handler, attrGetters ...AttrGetter) *Handler {
+	return NewHandlerWithOptions(target, nil, attrGetters...)
+}
+
+// NewHandlerWithOptions returns a new Handler, like [NewHandler], that uses
+// the given options. If opts is nil, the default options are used.
+//
+// If the target is a *Handler with the same options, the getters are merged
+// into a copy of the target instead, so that they all run in one layer and
+// a getter used by both handlers runs once. The new getters' attributes
+// come first.
+//
+// Panics if target handler is nil, receives zero [AttrGetter] instances, or
+// any [AttrGetter] references are nil.
+func NewHandlerWithOptions(
+	target slog.Handler,
+	opts *HandlerOptions,
+	attrGetters ...AttrGetter,
+) *Handler {
 	if target == nil {
 		panic("target is nil")
 	}
 
-	return &Handler{
-		attrGetter: concat(attrGetters),
-		target:     target,
+	h := &Handler{
+		attrGetter:   concat(attrGetters),
+		target:       target,
+		countsEvents: !wrapsHandler(target),
+	}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if len(h.opts.GroupSeparator) == 0 {
+		h.opts.GroupSeparator = "."
+	}
+	if len(h.opts.ContextKey) == 0 {
+		h.opts.ContextKey = "context"
+	}
+	if len(h.opts.TemplateKey) == 0 {
+		h.opts.TemplateKey = "msg_template"
+	}
+
+	if h.opts.EmitOnce {
+		h.onceOwner = &onceOwner{}
+	}
+	if h.opts.Levels != nil {
+		h.componentLevel = &componentLevel{}
+	}
+
+	if inner, ok := target.(*Handler); ok && inner.opts == h.opts {
+		return inner.merge(h.attrGetter)
+	}
+
+	return h
+}
+
+// Unwrap returns the target handler.
+func (h *Handler) Unwrap() slog.Handler {
+	return h.target
+}
+
+// wrapsHandler returns whether h is, or wraps, a *Handler.
+func wrapsHandler(h slog.Handler) bool {
+	for h != nil {
+		switch t := h.(type) {
+		case *Handler:
+			return true
+		case *BufferHandler:
+			h = t.target
+		case interface{ Unwrap() slog.Handler }:
+			h = t.Unwrap()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// merge returns a copy of the handler that also uses the getter, dropping
+// any getter that is identical to one already used.
+func (h *Handler) merge(g AttrGetter) *Handler {
+	var getters []AttrGetter
+	for _, g1 := range append(splitConcat(g), splitConcat(h.attrGetter)...) {
+		if !slices.ContainsFunc(getters, func(g2 AttrGetter) bool {
+			return sameAttrGetter(g1, g2)
+		}) {
+			getters = append(getters, g1)
+		}
 	}
+
+	h2 := *h
+	h2.attrGetter = concat(getters)
+	return &h2
 }
 
-// Enabled returns whether the target handler is enabled for the context
-// and level.
+// Enabled returns whether the handler is enabled for the context and level.
+// Unless [HandlerOptions.Levels] is set, this is whether the target handler
+// is enabled.
 func (h *Handler) Enabled(ctx context.Context, level slog.Level) bool {
+	if h.opts.Levels != nil {
+		return level >= h.componentLevel.levelFor(h.opts.Levels)
+	}
 	return h.target.Enabled(ctx, level)
 }
 
 // Handle delegates handling the record and any attributes gathered from the
-// context to the target handler.
+// context to the target handler. If the context has an event, see
+// [BeginEvent], the record is counted towards it, once however many
+// Handlers it passes through.
 func (h *Handler) Handle(ctx context.Context, rec slog.Record) error {
-	if attrs := h.attrGetter.GetAttrs(ctx); len(attrs) > 0 {
+	rec = h.resolve(ctx, rec, h.opts.EmitOnce && !isEventRecord(ctx))
+	return h.target.Handle(ctx, rec)
+}
+
+// resolve counts the record towards the context's event and returns the
+// record to pass to the target handler, emitting the context attributes
+// once if emitOnce is set.
+func (h *Handler) resolve(
+	ctx context.Context,
+	rec slog.Record,
+	emitOnce bool,
+) slog.Record {
+	if h.countsEvents {
+		countEventRecord(ctx, rec.Level)
+	}
+
+	buf := attrsPool.Get().(*[]slog.Attr)
+	attrs := appendAttrs(ctx, h.attrGetter, (*buf)[:0])
+	if emitOnce {
+		attrs = h.emitOnce(ctx, attrs)
+	}
+	if h.opts.AnnotateDone {
+		attrs = appendDoneAttrs(ctx, attrs)
+	}
+	rec = h.record(rec, attrs)
+
+	// The record holds copies of the attributes, so the buffer can be
+	// reused.
+	clear(attrs)
+	*buf = attrs[:0]
+	attrsPool.Put(buf)
+
+	return rec
+}
+
+// record returns the record, including the context attributes, to pass to
+// the target handler.
+func (h *Handler) record(rec slog.Record, attrs []slog.Attr) slog.Record {
+	// Templates name the keys used by the getters, so they are filled in
+	// before the keys are restyled.
+	var template string
+	if h.opts.MessageTemplates {
+		rec, template = h.fillMessage(rec, attrs)
+	}
+
+	if h.opts.KeyStyle != KeyAsIs {
+		restyleKeys(h.opts.KeyStyle, attrs)
+	}
+	if h.opts.Layout != nil {
+		attrs = h.opts.Layout.LayoutAttrs(h.component, attrs)
+	}
+
+	switch {
+	case h.opts.GroupStyle != GroupNested:
+		rec = h.flatRecord(rec, attrs)
+
+	case len(h.groups) > 0 && h.opts.Layout != nil:
+		rec = h.groupedRecord(rec, attrs, nil)
+
+	case len(h.groups) > 0:
+		rec = h.groupedRecord(rec, nil, attrs)
+
+	case len(attrs) > 0:
 		// From https://pkg.go.dev/log/slog#hdr-Working_with_Records:
 		// "Before modifying a Record, use Record.Clone to create a copy"
+		//
+		// Clone only clips the record's attribute slice, so it does not
+		// allocate; any allocation comes from AddAttrs growing that slice.
 		rec = rec.Clone()
 		rec.AddAttrs(attrs...)
 	}
 
-	return h.target.Handle(ctx, rec)
+	if len(template) > 0 {
+		rec = rec.Clone()
+		rec.AddAttrs(slog.String(h.opts.TemplateKey, template))
+	}
+
+	return rec
 }
 
 // WithAttrs returns a handler that will include the given attributes when
-// handling records.
+// handling records. The attributes are passed to the target handler
+// immediately, so any work the target does to prepare them is done once,
+// unless a group is open and [HandlerOptions.MessageTemplates] or
+// [HandlerOptions.Layout] is set. Then the handler keeps them, and nests
+// them in the group of each record it handles.
 func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
-	return &Handler{
-		attrGetter: h.attrGetter,
-		target:     h.target.WithAttrs(attrs),
+	if len(attrs) == 0 {
+		return h
 	}
+
+	h2 := *h
+	switch {
+	case h.opts.GroupStyle != GroupNested:
+		attrs = appendFlatAttrs(nil, h.prefix, h.opts.GroupSeparator, attrs)
+	case len(h.groups) > 0:
+		h2.groups = slices.Clone(h.groups)
+		last := &h2.groups[len(h2.groups)-1]
+		last.attrs = append(slices.Clip(last.attrs), attrs...)
+		return &h2
+	}
+	h2.target = h.target.WithAttrs(attrs)
+	return &h2
 }
 
 // WithGroup returns a handler that will group attributes when handling
-// records.
+// records. If the name is empty, WithGroup returns the receiver.
 func (h *Handler) WithGroup(name string) slog.Handler {
-	return &Handler{
-		attrGetter: h.attrGetter,
-		target:     h.target.WithGroup(name),
+	if len(name) == 0 {
+		return h
+	}
+
+	h2 := *h
+	switch {
+	case h.opts.GroupStyle != GroupNested:
+		h2.prefix = h.prefix + name + h.opts.GroupSeparator
+	case h.opts.MessageTemplates || h.opts.Layout != nil:
+		h2.groups = append(slices.Clip(h.groups), handlerGroup{name: name})
+	default:
+		h2.target = h.target.WithGroup(name)
+	}
+	h2.component = joinComponent(h.component, name)
+	if h.opts.Levels != nil {
+		h2.componentLevel = &componentLevel{component: h2.component}
 	}
+	return &h2
 }
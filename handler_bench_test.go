@@ -0,0 +1,157 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+)
+
+func benchmarkContext() context.Context {
+	ctx := context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+	ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+	return context.WithValue(ctx, pifCtxKey, pifAttrValue)
+}
+
+func benchmarkHandle(b *testing.B, getters ...slogctx.AttrGetter) {
+	benchmarkHandleRecord(b,
+		slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark", 0),
+		getters...,
+	)
+}
+
+func benchmarkHandleRecord(
+	b *testing.B,
+	rec slog.Record,
+	getters ...slogctx.AttrGetter,
+) {
+	h := slogctx.NewHandler(NewHandlerSpy(), getters...)
+	ctx := benchmarkContext()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Handle(ctx, rec)
+	}
+}
+
+// BenchmarkHandle_GetAttrs hides AttrAppender so every getter allocates the
+// slice it returns, as all getters did before AttrAppender was introduced.
+func BenchmarkHandle_GetAttrs(b *testing.B) {
+	benchmarkHandle(b,
+		slogctx.AttrGetterFunc(fooGetter.GetAttrs),
+		slogctx.AttrGetterFunc(barGetter.GetAttrs),
+		slogctx.AttrGetterFunc(pifGetter.GetAttrs),
+	)
+}
+
+func BenchmarkHandle_AppendAttrs(b *testing.B) {
+	benchmarkHandle(b, fooGetter, barGetter, pifGetter)
+}
+
+func BenchmarkHandle_Group(b *testing.B) {
+	benchmarkHandle(b, slogctx.Group(groupName, fooGetter, barGetter, pifGetter))
+}
+
+// BenchmarkHandle_ManyAttrs adds context attributes to a record that already
+// has more attributes than a record stores inline, so they must be added to
+// the record's backing slice.
+func BenchmarkHandle_ManyAttrs(b *testing.B) {
+	rec := slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark", 0)
+	rec.AddAttrs(fooAttr, barAttr, pifAttr, fooAttr, barAttr)
+	benchmarkHandleRecord(b, rec, fooGetter, barGetter, pifGetter)
+}
+
+// BenchmarkRecord_AddAttrs and BenchmarkRecord_CloneAddAttrs show that the
+// cost of adding attributes to a record comes from AddAttrs rather than the
+// Clone required before modifying it.
+func BenchmarkRecord_AddAttrs(b *testing.B) {
+	benchmarkAddAttrs(b, func(rec slog.Record) slog.Record { return rec })
+}
+
+func BenchmarkRecord_CloneAddAttrs(b *testing.B) {
+	benchmarkAddAttrs(b, slog.Record.Clone)
+}
+
+func benchmarkAddAttrs(b *testing.B, clone func(slog.Record) slog.Record) {
+	rec := slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark", 0)
+	rec.AddAttrs(fooAttr, barAttr, pifAttr, fooAttr, barAttr)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r := clone(rec)
+		r.AddAttrs(fooAttr, barAttr, pifAttr)
+	}
+}
+
+func BenchmarkHandler_WithAttrsWithGroup(b *testing.B) {
+	var h slog.Handler = slogctx.NewHandler(
+		slog.NewJSONHandler(io.Discard, nil),
+		fooGetter, barGetter, pifGetter,
+	)
+	h = h.WithAttrs([]slog.Attr{fooAttr, barAttr}).WithGroup(groupName)
+	ctx := benchmarkContext()
+	rec := slog.NewRecord(time.Now(), slog.LevelInfo, "benchmark", 0)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Handle(ctx, rec)
+	}
+}
+
+// BenchmarkHandler_EnabledLevels and BenchmarkLevels_LevelFor compare the
+// override cached by a handler made by a WithGroup and WithAttrs chain with
+// searching the overrides for its component on each call.
+func BenchmarkHandler_EnabledLevels(b *testing.B) {
+	levels := benchmarkLevels()
+	var h slog.Handler = slogctx.NewHandlerWithOptions(
+		slog.NewJSONHandler(io.Discard, nil),
+		&slogctx.HandlerOptions{Levels: levels},
+		fooGetter,
+	)
+	h = h.WithGroup("db").WithAttrs([]slog.Attr{fooAttr}).WithGroup("pool").
+		WithGroup("conn")
+	ctx := benchmarkContext()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Enabled(ctx, slog.LevelDebug)
+	}
+}
+
+func BenchmarkLevels_LevelFor(b *testing.B) {
+	levels := benchmarkLevels()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = levels.LevelFor("db.pool.conn")
+	}
+}
+
+func benchmarkLevels() *slogctx.Levels {
+	levels := slogctx.NewLevels(slog.LevelInfo)
+	levels.SetOverride("db", slog.LevelDebug)
+	levels.SetOverride("http", slog.LevelWarn)
+	return levels
+}
@@ -15,8 +15,10 @@
 package slogctx_test
 
 import (
+	"context"
 	"log/slog"
 	"os"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -73,4 +75,51 @@ var _ = Describe("Creating a new handler", func() {
 			})
 		})
 	})
+
+	When("the target is a Handler", func() {
+		var (
+			spyHandler *HandlerSpy
+			inner      *slogctx.Handler
+			ctx        context.Context
+		)
+
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+			ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+			spyHandler = NewHandlerSpy()
+			inner = slogctx.NewHandler(spyHandler, fooGetter)
+		})
+
+		It("merges the getters into one handler", func() {
+			h := slogctx.NewHandler(inner, barGetter, fooGetter)
+			Expect(h.Unwrap()).To(BeIdenticalTo(spyHandler))
+
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+			Expect(h.Handle(ctx, rec)).To(Succeed())
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(Equal(
+				[]slog.Attr{barAttr, fooAttr},
+			))
+		})
+
+		It("does not modify the target", func() {
+			_ = slogctx.NewHandler(inner, barGetter)
+
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+			Expect(inner.Handle(ctx, rec)).To(Succeed())
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(Equal(
+				[]slog.Attr{fooAttr},
+			))
+		})
+
+		Context("with different options", func() {
+
+			It("wraps the target", func() {
+				h := slogctx.NewHandlerWithOptions(inner,
+					&slogctx.HandlerOptions{KeyStyle: slogctx.KeySnakeCase},
+					barGetter,
+				)
+				Expect(h.Unwrap()).To(BeIdenticalTo(inner))
+			})
+		})
+	})
 })
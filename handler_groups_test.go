@@ -0,0 +1,171 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Passing groups to the target handler", func() {
+	var (
+		spyHandler *HandlerSpy
+		opts       *slogctx.HandlerOptions
+		handler    slog.Handler
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+		spyHandler = NewHandlerSpy()
+	})
+
+	JustBeforeEach(func() {
+		handler = slogctx.NewHandlerWithOptions(spyHandler, opts,
+			slogctx.Group(groupName, fooGetter, slogctx.Group("b", barGetter)),
+		)
+	})
+
+	handle := func(h slog.Handler, attrs ...slog.Attr) []slog.Attr {
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+		rec.AddAttrs(attrs...)
+		Expect(h.Handle(ctx, rec)).To(Succeed())
+		return GetAttrs(spyHandler.HandleSpy.Rec)
+	}
+
+	When("groups are flattened", func() {
+
+		BeforeEach(func() {
+			opts = &slogctx.HandlerOptions{GroupStyle: slogctx.GroupFlatten}
+		})
+
+		It("joins the keys of the context attributes", func() {
+			Expect(handle(handler)).To(Equal([]slog.Attr{
+				slog.Int("ziz.foo", fooAttrValue),
+				slog.String("ziz.b.bar", barAttrValue),
+			}))
+		})
+
+		It("joins the keys of the record's attributes", func() {
+			Expect(handle(handler, slog.Group("g", pifAttr))).To(
+				ContainElement(slog.Bool("g.pif", pifAttrValue)),
+			)
+		})
+
+		Context("and a group is opened", func() {
+
+			It("does not pass the group to the target handler", func() {
+				handler.WithGroup("req")
+				Expect(spyHandler.WithGroupSpy.Name).To(BeEmpty())
+			})
+
+			It("prefixes the keys of all attributes", func() {
+				h := handler.WithGroup("req")
+				Expect(handle(h, pifAttr)).To(Equal([]slog.Attr{
+					slog.Bool("req.pif", pifAttrValue),
+					slog.Int("req.ziz.foo", fooAttrValue),
+					slog.String("req.ziz.b.bar", barAttrValue),
+				}))
+			})
+
+			It("ignores empty attributes", func() {
+				h := handler.WithGroup("req")
+				Expect(handle(h, slog.Attr{}, pifAttr)).To(Equal([]slog.Attr{
+					slog.Bool("req.pif", pifAttrValue),
+					slog.Int("req.ziz.foo", fooAttrValue),
+					slog.String("req.ziz.b.bar", barAttrValue),
+				}))
+			})
+
+			It("prefixes the keys of attributes added to the handler", func() {
+				handler.WithGroup("req").WithAttrs([]slog.Attr{slog.Group("g", pifAttr)})
+				Expect(spyHandler.WithAttrsSpy.Attrs).To(Equal([]slog.Attr{
+					slog.Bool("req.g.pif", pifAttrValue),
+				}))
+			})
+		})
+
+		Context("and a separator is set", func() {
+
+			BeforeEach(func() {
+				opts.GroupSeparator = "_"
+			})
+
+			It("joins the keys with the separator", func() {
+				Expect(handle(handler.WithGroup("req"))).To(
+					ContainElement(slog.String("req_ziz_b_bar", barAttrValue)),
+				)
+			})
+		})
+	})
+
+	When("a layout is set", func() {
+
+		BeforeEach(func() {
+			opts = &slogctx.HandlerOptions{Layout: componentLayout{}}
+		})
+
+		It("adds the laid out context attributes at the top level", func() {
+			h := handler.WithGroup("req").WithAttrs([]slog.Attr{pifAttr})
+			Expect(handle(h, slog.Int("n", 1))).To(Equal([]slog.Attr{
+				slog.String("component", "req"),
+				slog.Group(groupName, fooAttr, slog.Group("b", barAttr)),
+				slog.Group("req", pifAttr, slog.Int("n", 1)),
+			}))
+		})
+	})
+
+	When("the context attributes are encoded as JSON", func() {
+
+		BeforeEach(func() {
+			opts = &slogctx.HandlerOptions{GroupStyle: slogctx.GroupJSON}
+		})
+
+		It("adds a single string attribute", func() {
+			Expect(handle(handler, slog.Group("g", pifAttr))).To(Equal([]slog.Attr{
+				slog.Bool("g.pif", pifAttrValue),
+				slog.String("context", `{"ziz":{"foo":42,"b":{"bar":"oom"}}}`),
+			}))
+		})
+
+		Context("and a key is set", func() {
+
+			BeforeEach(func() {
+				opts.ContextKey = "ctx"
+			})
+
+			It("uses the key", func() {
+				Expect(handle(handler.WithGroup("req"))).To(ConsistOf(
+					slog.String("req.ctx", `{"ziz":{"foo":42,"b":{"bar":"oom"}}}`),
+				))
+			})
+		})
+	})
+})
+
+// componentLayout is a layout that adds the component before the context
+// attributes.
+type componentLayout struct{}
+
+func (componentLayout) LayoutAttrs(component string, attrs []slog.Attr) []slog.Attr {
+	return append([]slog.Attr{slog.String("component", component)}, attrs...)
+}
@@ -17,6 +17,7 @@ package slogctx_test
 import (
 	"context"
 	"log/slog"
+	"testing"
 	"time"
 
 	"github.com/pfflabs/slogctx"
@@ -147,4 +148,21 @@ var _ = Describe("Handling a record", func() {
 			)
 		})
 	})
+
+	When("the getters can append attributes", func() {
+
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+			ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+			getters = []slogctx.AttrGetter{fooGetter, barGetter}
+		})
+
+		It("does not allocate", func() {
+			h := slogctx.NewHandler(spyHandler, getters...)
+			allocs := testing.AllocsPerRun(100, func() {
+				_ = h.Handle(ctx, rec)
+			})
+			Expect(allocs).To(BeZero())
+		})
+	})
 })
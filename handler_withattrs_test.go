@@ -52,3 +52,11 @@ var _ = Describe("Creating a new handler with attributes", func() {
 		})
 	})
 })
+
+var _ = When("creating a new handler with zero attributes", func() {
+	handler := slogctx.NewHandler(slog.NewTextHandler(&strings.Builder{}, nil), noopGetter)
+
+	It("returns the same handler", func() {
+		Expect(handler.WithAttrs(nil)).To(BeIdenticalTo(handler))
+	})
+})
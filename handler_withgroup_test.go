@@ -56,3 +56,11 @@ var _ = Describe("Creating a new handler with an attribute group", func() {
 		})
 	})
 })
+
+var _ = When("creating a new handler with an empty group name", func() {
+	handler := slogctx.NewHandler(slog.NewTextHandler(&strings.Builder{}, nil), noopGetter)
+
+	It("returns the same handler", func() {
+		Expect(handler.WithGroup("")).To(BeIdenticalTo(handler))
+	})
+})
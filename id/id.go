@@ -0,0 +1,140 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package id generates correlation IDs, stores them in a
+// [context.Context] with [slogctx.WithCorrelationID], and provides an
+// [slogctx.AttrGetter] for them.
+//
+//	ctx = id.WithNew(ctx, id.UUIDv7)
+//	h = slogctx.NewHandler(h, id.Attr("request_id"))
+//
+// Use [slogctx.Fork] to derive child IDs for work done in parallel.
+package id
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"io"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+)
+
+// Source is the source of the time and randomness used to generate IDs.
+// The zero Source uses [time.Now] and [crypto/rand.Reader].
+type Source struct {
+	// Rand, if set, is read for the random part of IDs, e.g. so that tests
+	// are deterministic.
+	Rand io.Reader
+
+	// Now, if set, is used to tell the time.
+	Now func() time.Time
+}
+
+// UUIDv7 returns a new version 7 UUID, as defined by RFC 9562, which sorts
+// by the time it was generated, e.g. "01890a5d-ac96-774b-bcce-b302099a8057".
+//
+// Panics if reading from Rand fails.
+func (s Source) UUIDv7() string {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[:8], uint64(s.now().UnixMilli())<<16)
+	s.read(u[6:])
+	u[6] = u[6]&0x0f | 0x70 // Version 7.
+	u[8] = u[8]&0x3f | 0x80 // Variant 10.
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:])
+	return string(buf[:])
+}
+
+// crockford is the alphabet of Crockford's base32, used by ULIDs.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// ULID returns a new ULID, as defined by https://github.com/ulid/spec, which
+// sorts by the time it was generated, e.g. "01H4556XYE4SNWBEMW08JGYYNT".
+//
+// Panics if reading from Rand fails.
+func (s Source) ULID() string {
+	var u [16]byte
+	binary.BigEndian.PutUint64(u[:8], uint64(s.now().UnixMilli())<<16)
+	s.read(u[6:])
+
+	// Encode the 128 bits as 26 characters of 5 bits, from the least
+	// significant, so the first character holds the top 3 bits.
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+	var buf [26]byte
+	for i := len(buf) - 1; i >= 0; i-- {
+		buf[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(buf[:])
+}
+
+func (s Source) now() time.Time {
+	if s.Now != nil {
+		return s.Now()
+	}
+	return time.Now()
+}
+
+func (s Source) read(b []byte) {
+	r := s.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	if _, err := io.ReadFull(r, b); err != nil {
+		panic("reading random bytes: " + err.Error())
+	}
+}
+
+// UUIDv7 returns a new version 7 UUID using the zero [Source].
+func UUIDv7() string {
+	return Source{}.UUIDv7()
+}
+
+// ULID returns a new ULID using the zero [Source].
+func ULID() string {
+	return Source{}.ULID()
+}
+
+// WithNew returns a copy of ctx with a correlation ID made by newID, e.g.
+// [UUIDv7].
+//
+// Panics if newID is nil or returns an empty ID.
+func WithNew(ctx context.Context, newID func() string) context.Context {
+	if newID == nil {
+		panic("newID is nil")
+	}
+	return slogctx.WithCorrelationID(ctx, newID())
+}
+
+// Attr returns an [slogctx.AttrGetter] that adds the correlation ID of a
+// context, if any, including those made by [slogctx.Fork].
+//
+// Panics if key is empty.
+func Attr(key string) slogctx.AttrGetter {
+	return slogctx.Attr(key, slogctx.CorrelationID)
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package id_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+	"github.com/pfflabs/slogctx/id"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Generating IDs", func() {
+	var source id.Source
+
+	BeforeEach(func() {
+		source = id.Source{
+			Rand: bytes.NewReader([]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}),
+			Now: func() time.Time {
+				return time.UnixMilli(1700000000123)
+			},
+		}
+	})
+
+	It("generates a UUIDv7", func() {
+		Expect(source.UUIDv7()).To(Equal("018bcfe5-687b-7102-8304-05060708090a"))
+	})
+
+	It("generates a ULID", func() {
+		Expect(source.ULID()).To(Equal("01HF7YAT3V041061050R3GG28A"))
+	})
+
+	It("generates different IDs by default", func() {
+		Expect(id.UUIDv7()).NotTo(Equal(id.UUIDv7()))
+		Expect(id.ULID()).NotTo(Equal(id.ULID()))
+	})
+
+	When("reading random bytes fails", func() {
+
+		It("panics", func() {
+			source.Rand = &bytes.Reader{}
+			Expect(func() { source.ULID() }).To(PanicWith("reading random bytes: EOF"))
+		})
+	})
+})
+
+var _ = Describe("Getting the ID from a context", func() {
+
+	It("adds the ID and those of forked contexts", func() {
+		ctx := id.WithNew(context.Background(), func() string { return "abc123" })
+		getter := id.Attr("request_id")
+
+		Expect(getter.GetAttrs(ctx)).To(Equal(
+			[]slog.Attr{slog.String("request_id", "abc123")},
+		))
+		Expect(getter.GetAttrs(slogctx.Fork(ctx))).To(Equal(
+			[]slog.Attr{slog.String("request_id", "abc123.1")},
+		))
+	})
+})
@@ -0,0 +1,173 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode"
+	"unicode/utf8"
+)
+
+// KeyStyle is a naming convention a [Handler] applies to the keys of the
+// context attributes.
+//
+// Keys are split into words at '_', '-' and ' ' characters and where the
+// case changes, e.g. "userID", "user_id" and "User-Id" all contain the words
+// "user" and "id". Each part of a key separated by a '.', e.g. "http" and
+// "requestMethod" in "http.requestMethod", is converted separately.
+type KeyStyle int
+
+const (
+	// KeyAsIs leaves keys unchanged.
+	KeyAsIs KeyStyle = iota
+
+	// KeySnakeCase converts keys to snake_case.
+	KeySnakeCase
+
+	// KeyCamelCase converts keys to camelCase.
+	KeyCamelCase
+
+	// KeyKebabCase converts keys to kebab-case.
+	KeyKebabCase
+)
+
+// maxCachedKeys limits the number of converted keys cached for each
+// style, in case keys are created dynamically.
+const maxCachedKeys = 4096
+
+type keyCache struct {
+	keys sync.Map
+	n    atomic.Int32
+}
+
+var keyCaches [KeyKebabCase + 1]keyCache
+
+// Key returns the key converted to the style.
+func (s KeyStyle) Key(key string) string {
+	if s <= KeyAsIs || s > KeyKebabCase {
+		return key
+	}
+
+	c := &keyCaches[s]
+	if k, ok := c.keys.Load(key); ok {
+		return k.(string)
+	}
+
+	k := s.convert(key)
+	if c.n.Load() < maxCachedKeys {
+		if _, loaded := c.keys.LoadOrStore(key, k); !loaded {
+			c.n.Add(1)
+		}
+	}
+
+	return k
+}
+
+func (s KeyStyle) convert(key string) string {
+	var b strings.Builder
+	b.Grow(len(key) + 4)
+
+	for i, part := range strings.Split(key, ".") {
+		if i > 0 {
+			b.WriteByte('.')
+		}
+
+		for j, word := range splitWords(part) {
+			switch s {
+			case KeySnakeCase:
+				if j > 0 {
+					b.WriteByte('_')
+				}
+				b.WriteString(strings.ToLower(word))
+
+			case KeyKebabCase:
+				if j > 0 {
+					b.WriteByte('-')
+				}
+				b.WriteString(strings.ToLower(word))
+
+			case KeyCamelCase:
+				word = strings.ToLower(word)
+				if j > 0 {
+					r, size := utf8.DecodeRuneInString(word)
+					b.WriteRune(unicode.ToUpper(r))
+					word = word[size:]
+				}
+				b.WriteString(word)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// splitWords splits s into words as described by [KeyStyle].
+func splitWords(s string) []string {
+	var (
+		words []string
+		start = -1
+		prev  rune
+	)
+
+	for i, r := range s {
+		if r == '_' || r == '-' || r == ' ' {
+			if start >= 0 {
+				words = append(words, s[start:i])
+				start = -1
+			}
+			prev = r
+			continue
+		}
+
+		if start >= 0 && unicode.IsUpper(r) {
+			next, _ := utf8.DecodeRuneInString(s[i+utf8.RuneLen(r):])
+			// Split "userID" before "I" and "HTTPServer" before "S".
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				(unicode.IsUpper(prev) && unicode.IsLower(next)) {
+				words = append(words, s[start:i])
+				start = i
+			}
+		}
+
+		if start < 0 {
+			start = i
+		}
+		prev = r
+	}
+
+	if start >= 0 {
+		words = append(words, s[start:])
+	}
+	return words
+}
+
+// restyleKeys converts the keys of attrs, and those in groups, to the
+// style. The attributes are modified in place but groups are copied, as
+// they may be shared with the getter.
+func restyleKeys(s KeyStyle, attrs []slog.Attr) {
+	for i, a := range attrs {
+		a.Key = s.Key(a.Key)
+		if a.Value.Kind() == slog.KindGroup {
+			group := make([]slog.Attr, len(a.Value.Group()))
+			copy(group, a.Value.Group())
+			restyleKeys(s, group)
+			a.Value = slog.GroupValue(group...)
+		}
+		attrs[i] = a
+	}
+}
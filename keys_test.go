@@ -0,0 +1,71 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Converting keys to a naming convention", func() {
+
+	DescribeTable("converting a key",
+		func(key, snake, camel, kebab string) {
+			Expect(slogctx.KeySnakeCase.Key(key)).To(Equal(snake))
+			Expect(slogctx.KeyCamelCase.Key(key)).To(Equal(camel))
+			Expect(slogctx.KeyKebabCase.Key(key)).To(Equal(kebab))
+			Expect(slogctx.KeyAsIs.Key(key)).To(Equal(key))
+		},
+		Entry(nil, "user", "user", "user", "user"),
+		Entry(nil, "userID", "user_id", "userId", "user-id"),
+		Entry(nil, "user_id", "user_id", "userId", "user-id"),
+		Entry(nil, "User-Agent", "user_agent", "userAgent", "user-agent"),
+		Entry(nil, "HTTPServerName", "http_server_name", "httpServerName", "http-server-name"),
+		Entry(nil, "http.requestMethod", "http.request_method", "http.requestMethod", "http.request-method"),
+		Entry(nil, "v2Name", "v2_name", "v2Name", "v2-name"),
+		Entry(nil, "__x__", "x", "x", "x"),
+	)
+
+	When("a handler has a key style", func() {
+		spyHandler := NewHandlerSpy()
+		handler := slogctx.NewHandlerWithOptions(spyHandler,
+			&slogctx.HandlerOptions{KeyStyle: slogctx.KeySnakeCase},
+			slogctx.Group("reqInfo", slogctx.Attr("userID", func(context.Context) (string, bool) {
+				return "u1", true
+			})),
+		)
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+		rec.AddAttrs(slog.String("recordKey", "v"))
+		_ = handler.Handle(context.Background(), rec)
+
+		It("converts the keys of the context attributes", func() {
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(ContainElement(
+				slog.Group("req_info", slog.String("user_id", "u1")),
+			))
+		})
+
+		It("does not convert the keys of the record's attributes", func() {
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(ContainElement(
+				slog.String("recordKey", "v"),
+			))
+		})
+	})
+})
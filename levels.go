@@ -0,0 +1,165 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Levels is a [log/slog.Leveler] with a base level that can be overridden
+// for individual components, where a component is named by the groups
+// opened using [Handler.WithGroup] joined with a '.', e.g. "db.pool".
+//
+// Levels is safe for concurrent use.
+type Levels struct {
+	base slog.LevelVar
+
+	mu        sync.Mutex
+	overrides atomic.Pointer[map[string]slog.Level]
+}
+
+var _ slog.Leveler = (*Levels)(nil)
+
+// NewLevels returns a new Levels with the base level.
+func NewLevels(base slog.Level) *Levels {
+	l := &Levels{}
+	l.base.Set(base)
+	return l
+}
+
+// Level returns the base level.
+func (l *Levels) Level() slog.Level {
+	return l.base.Level()
+}
+
+// SetLevel sets the base level.
+func (l *Levels) SetLevel(level slog.Level) {
+	l.base.Set(level)
+}
+
+// LevelFor returns the level for the component. This is the level of the
+// override for the component, or its closest parent component (e.g. "db"
+// for "db.pool"), or the base level if there are no overrides.
+func (l *Levels) LevelFor(component string) slog.Level {
+	if p := l.overrides.Load(); p != nil && len(component) > 0 {
+		if level, ok := overrideFor(*p, component); ok {
+			return level
+		}
+	}
+
+	return l.base.Level()
+}
+
+// overrideFor returns the level of the override for the component, or its
+// closest parent component, if any.
+func overrideFor(
+	overrides map[string]slog.Level,
+	component string,
+) (slog.Level, bool) {
+	for {
+		if level, ok := overrides[component]; ok {
+			return level, true
+		}
+
+		i := strings.LastIndexByte(component, '.')
+		if i < 0 {
+			return 0, false
+		}
+		component = component[:i]
+	}
+}
+
+// componentLevel caches the override for a component, so a handler does
+// not search the overrides for each record.
+type componentLevel struct {
+	component string
+	cached    atomic.Pointer[cachedOverride]
+}
+
+// cachedOverride is the override, if any, found in a set of overrides.
+type cachedOverride struct {
+	overrides *map[string]slog.Level
+	level     slog.Level
+	ok        bool
+}
+
+// levelFor returns the level for the component, like [Levels.LevelFor],
+// searching the overrides only when they have changed since the last call.
+func (c *componentLevel) levelFor(l *Levels) slog.Level {
+	p := l.overrides.Load()
+	if p == nil || len(c.component) == 0 {
+		return l.base.Level()
+	}
+
+	o := c.cached.Load()
+	if o == nil || o.overrides != p {
+		o = &cachedOverride{overrides: p}
+		o.level, o.ok = overrideFor(*p, c.component)
+		c.cached.Store(o)
+	}
+
+	if o.ok {
+		return o.level
+	}
+	return l.base.Level()
+}
+
+// SetOverride sets the level for the component and its children.
+//
+// Panics if the component is empty.
+func (l *Levels) SetOverride(component string, level slog.Level) {
+	if len(component) == 0 {
+		panic("component is empty")
+	}
+
+	l.updateOverrides(func(overrides map[string]slog.Level) {
+		overrides[component] = level
+	})
+}
+
+// RemoveOverride removes the override for the component, if any.
+func (l *Levels) RemoveOverride(component string) {
+	l.updateOverrides(func(overrides map[string]slog.Level) {
+		delete(overrides, component)
+	})
+}
+
+// Overrides returns the overridden level for each component.
+func (l *Levels) Overrides() map[string]slog.Level {
+	if p := l.overrides.Load(); p != nil {
+		return maps.Clone(*p)
+	}
+	return map[string]slog.Level{}
+}
+
+func (l *Levels) updateOverrides(fn func(map[string]slog.Level)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	overrides := l.Overrides()
+	fn(overrides)
+	l.overrides.Store(&overrides)
+}
+
+func joinComponent(parent, name string) string {
+	if len(parent) == 0 {
+		return name
+	}
+	return parent + "." + name
+}
@@ -0,0 +1,120 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controlling the levels of a handler", func() {
+	var (
+		levels     *slogctx.Levels
+		spyHandler *HandlerSpy
+		handler    slog.Handler
+	)
+	ctx := context.Background()
+
+	BeforeEach(func() {
+		levels = slogctx.NewLevels(slog.LevelInfo)
+		spyHandler = NewHandlerSpy()
+		handler = slogctx.NewHandlerWithOptions(spyHandler, &slogctx.HandlerOptions{
+			Levels: levels,
+		}, noopGetter)
+	})
+
+	It("uses the base level", func() {
+		Expect(handler.Enabled(ctx, slog.LevelInfo)).To(BeTrue())
+		Expect(handler.Enabled(ctx, slog.LevelDebug)).To(BeFalse())
+	})
+
+	It("does not ask the target handler", func() {
+		handler.Enabled(ctx, slog.LevelInfo)
+		Expect(spyHandler.EnableSpy.Ctx).To(BeNil())
+	})
+
+	When("the base level is changed", func() {
+
+		BeforeEach(func() {
+			levels.SetLevel(slog.LevelDebug)
+		})
+
+		It("uses the new level", func() {
+			Expect(handler.Enabled(ctx, slog.LevelDebug)).To(BeTrue())
+		})
+	})
+
+	When("the level for a component is overridden", func() {
+
+		BeforeEach(func() {
+			levels.SetOverride("db", slog.LevelDebug)
+		})
+
+		It("uses the level for handlers with that group", func() {
+			Expect(handler.WithGroup("db").Enabled(ctx, slog.LevelDebug)).To(BeTrue())
+		})
+
+		It("uses the level for handlers with a child group", func() {
+			h := handler.WithGroup("db").WithAttrs([]slog.Attr{fooAttr}).WithGroup("pool")
+			Expect(h.Enabled(ctx, slog.LevelDebug)).To(BeTrue())
+		})
+
+		It("uses the base level for other handlers", func() {
+			Expect(handler.Enabled(ctx, slog.LevelDebug)).To(BeFalse())
+			Expect(handler.WithGroup("http").Enabled(ctx, slog.LevelDebug)).To(BeFalse())
+		})
+
+		It("returns the overrides", func() {
+			Expect(levels.Overrides()).To(Equal(map[string]slog.Level{
+				"db": slog.LevelDebug,
+			}))
+		})
+
+		Context("and then removed", func() {
+
+			BeforeEach(func() {
+				levels.RemoveOverride("db")
+			})
+
+			It("uses the base level", func() {
+				Expect(handler.WithGroup("db").Enabled(ctx, slog.LevelDebug)).To(BeFalse())
+			})
+		})
+
+		Context("and then changed for an existing handler", func() {
+
+			It("uses the new level", func() {
+				h := handler.WithGroup("db").WithGroup("pool")
+				Expect(h.Enabled(ctx, slog.LevelDebug)).To(BeTrue())
+
+				levels.SetOverride("db.pool", slog.LevelWarn)
+				Expect(h.Enabled(ctx, slog.LevelInfo)).To(BeFalse())
+			})
+		})
+	})
+
+	When("overriding the level for an empty component", func() {
+
+		It("panics", func() {
+			Expect(func() { levels.SetOverride("", slog.LevelDebug) }).
+				To(PanicWith("component is empty"))
+		})
+	})
+})
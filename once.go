@@ -0,0 +1,106 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"hash/maphash"
+	"log/slog"
+	"sync"
+)
+
+type onceCtxKey struct{}
+
+// onceScope holds, for each handler, the hash of the last value emitted
+// for each key of the context attributes.
+type onceScope struct {
+	mu   sync.Mutex
+	last map[*onceOwner]map[string]uint64
+}
+
+// onceOwner identifies a handler, and those derived from it by WithAttrs
+// and WithGroup, in an emit once scope.
+type onceOwner struct {
+	_ byte
+}
+
+var onceSeed = maphash.MakeSeed()
+
+// WithEmitOnce returns a copy of ctx that starts a scope, e.g. for a
+// request, in which a [Handler] with [HandlerOptions.EmitOnce] set emits
+// each context attribute only when its value changes. The scope includes
+// contexts derived from the returned context.
+func WithEmitOnce(ctx context.Context) context.Context {
+	return context.WithValue(ctx, onceCtxKey{}, &onceScope{
+		last: map[*onceOwner]map[string]uint64{},
+	})
+}
+
+// emitOnce returns the attributes of attrs that are the reference
+// attribute or have changed since the handler last emitted them in the
+// context's scope. The attributes are modified in place.
+func (h *Handler) emitOnce(ctx context.Context, attrs []slog.Attr) []slog.Attr {
+	scope, ok := ctx.Value(onceCtxKey{}).(*onceScope)
+	if !ok || len(attrs) == 0 {
+		return attrs
+	}
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	last, ok := scope.last[h.onceOwner]
+	if !ok {
+		last = map[string]uint64{}
+		scope.last[h.onceOwner] = last
+	}
+
+	n := 0
+	for _, a := range attrs {
+		sum := hashAttr(a)
+		if prev, ok := last[a.Key]; ok && prev == sum && a.Key != h.opts.ReferenceKey {
+			continue
+		}
+
+		last[a.Key] = sum
+		attrs[n] = a
+		n++
+	}
+	clear(attrs[n:])
+	return attrs[:n]
+}
+
+func hashAttr(a slog.Attr) uint64 {
+	var mh maphash.Hash
+	mh.SetSeed(onceSeed)
+	hashAttrs(&mh, []slog.Attr{a})
+	return mh.Sum64()
+}
+
+func hashAttrs(mh *maphash.Hash, attrs []slog.Attr) {
+	for _, a := range attrs {
+		mh.WriteString(a.Key)
+		mh.WriteByte(0)
+
+		v := a.Value.Resolve()
+		mh.WriteByte(byte(v.Kind()))
+		if v.Kind() == slog.KindGroup {
+			hashAttrs(mh, v.Group())
+			mh.WriteByte(1)
+			continue
+		}
+		mh.WriteString(v.String())
+		mh.WriteByte(0)
+	}
+}
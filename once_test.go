@@ -0,0 +1,112 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Emitting context attributes once", func() {
+	var (
+		spyHandler *HandlerSpy
+		handler    *slogctx.Handler
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+		spyHandler = NewHandlerSpy()
+		handler = slogctx.NewHandlerWithOptions(spyHandler,
+			&slogctx.HandlerOptions{EmitOnce: true, ReferenceKey: barAttrName},
+			fooGetter, barGetter,
+		)
+	})
+
+	handle := func(ctx context.Context) []slog.Attr {
+		rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+		Expect(handler.Handle(ctx, rec)).To(Succeed())
+		return GetAttrs(spyHandler.HandleSpy.Rec)
+	}
+
+	When("the context is in a scope", func() {
+
+		BeforeEach(func() {
+			ctx = slogctx.WithEmitOnce(ctx)
+		})
+
+		It("emits the attributes in full only for the first record", func() {
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{barAttr}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{barAttr}))
+		})
+
+		It("emits the attributes again when they change in a derived context", func() {
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+
+			derived := context.WithValue(ctx, fooCtxKey, 99)
+			Expect(handle(derived)).To(Equal([]slog.Attr{slog.Int(fooAttrName, 99), barAttr}))
+			Expect(handle(derived)).To(Equal([]slog.Attr{barAttr}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+		})
+
+		It("emits only the attributes that change for each record", func() {
+			handler = slogctx.NewHandlerWithOptions(spyHandler,
+				&slogctx.HandlerOptions{EmitOnce: true, ReferenceKey: barAttrName},
+				fooGetter, barGetter, slogctx.Sequence("seq"),
+			)
+			ctx = slogctx.WithSequence(ctx)
+
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr, slog.Uint64("seq", 1)}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{barAttr, slog.Uint64("seq", 2)}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{barAttr, slog.Uint64("seq", 3)}))
+		})
+
+		It("tracks the attributes emitted by each handler separately", func() {
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+
+			other := slogctx.NewHandlerWithOptions(spyHandler,
+				&slogctx.HandlerOptions{EmitOnce: true}, fooGetter,
+			)
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+			Expect(other.Handle(ctx, rec)).To(Succeed())
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(Equal([]slog.Attr{fooAttr}))
+		})
+
+		It("shares the state with derived handlers", func() {
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, "test", 0)
+			derived := handler.WithAttrs([]slog.Attr{pifAttr})
+			Expect(derived.Handle(ctx, rec)).To(Succeed())
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(Equal([]slog.Attr{barAttr}))
+		})
+	})
+
+	When("the context is not in a scope", func() {
+
+		It("always emits the attributes in full", func() {
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+			Expect(handle(ctx)).To(Equal([]slog.Attr{fooAttr, barAttr}))
+		})
+	})
+})
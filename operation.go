@@ -0,0 +1,154 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
+)
+
+// Keys and values of the attributes of the record logged when an operation
+// started by [Start] ends.
+const (
+	OperationDurationKey = "duration"
+	OperationOutcomeKey  = "outcome"
+	OperationErrorKey    = "error"
+
+	OperationSucceeded = "success"
+	OperationFailed    = "failure"
+)
+
+type opCtxKey struct{}
+
+// Start returns a copy of ctx for an operation, e.g. "charge_card", nested
+// in any operation started with ctx, and a function that ends it. The
+// operation's path, e.g. "checkout/charge_card", is added to records by an
+// [Operation] getter.
+//
+//	ctx, end := slogctx.Start(ctx, "charge_card")
+//	defer func() { end(err) }()
+//
+// Calling end logs a record with [log/slog.Default], using the returned
+// context, with the operation's duration and outcome, and the error if it
+// is not nil. Its message names the operation's path, e.g.
+// "checkout/charge_card ended", so the record identifies the operation even
+// without an [Operation] getter. The record is logged at
+// [log/slog.LevelInfo] if err is nil, otherwise at [log/slog.LevelError].
+// Only the first call to end logs a record.
+//
+// Panics if name is empty.
+func Start(ctx context.Context, name string) (context.Context, func(err error)) {
+	return StartLogger(ctx, slog.Default(), name)
+}
+
+// StartLogger is like [Start] but logs the record with the logger.
+//
+// Panics if logger is nil or name is empty.
+func StartLogger(
+	ctx context.Context,
+	logger *slog.Logger,
+	name string,
+) (context.Context, func(err error)) {
+	if logger == nil {
+		panic("logger is nil")
+	}
+	if len(name) == 0 {
+		panic("name is empty")
+	}
+
+	path := name
+	if parent, ok := ctx.Value(opCtxKey{}).(string); ok {
+		path = parent + "/" + name
+	}
+	ctx = context.WithValue(ctx, opCtxKey{}, path)
+
+	start := clockNow(ctx)
+	var once sync.Once
+	return ctx, func(err error) {
+		// The record's source is the caller of end.
+		var pcs [1]uintptr
+		runtime.Callers(2, pcs[:])
+
+		once.Do(func() {
+			logEnd(ctx, logger, path, start, pcs[0], err)
+		})
+	}
+}
+
+// logEnd logs the record for the end of an operation.
+func logEnd(
+	ctx context.Context,
+	logger *slog.Logger,
+	path string,
+	start time.Time,
+	pc uintptr,
+	err error,
+) {
+	level, outcome := slog.LevelInfo, OperationSucceeded
+	if err != nil {
+		level, outcome = slog.LevelError, OperationFailed
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	now := clockNow(ctx)
+	rec := slog.NewRecord(now, level, path+" ended", pc)
+	rec.AddAttrs(
+		slog.Duration(OperationDurationKey, now.Sub(start)),
+		slog.String(OperationOutcomeKey, outcome),
+	)
+	if err != nil {
+		rec.AddAttrs(slog.Any(OperationErrorKey, err))
+	}
+
+	_ = logger.Handler().Handle(ctx, rec)
+}
+
+type operationAttrGetter struct {
+	key string
+}
+
+// Operation returns an [AttrGetter] that adds the path of the operations
+// started by [Start], e.g. "checkout/charge_card".
+//
+// Panics if key is empty.
+func Operation(key string) AttrGetter {
+	validateKey(key)
+	return &operationAttrGetter{key: key}
+}
+
+func (o *operationAttrGetter) attrKey() string {
+	return o.key
+}
+
+func (o *operationAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return o.AppendAttrs(ctx, nil)
+}
+
+func (o *operationAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	path, ok := ctx.Value(opCtxKey{}).(string)
+	if !ok {
+		return dst
+	}
+
+	return append(dst, slog.String(o.key, path))
+}
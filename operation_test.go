@@ -0,0 +1,124 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Starting operations", func() {
+	var (
+		spyHandler *HandlerSpy
+		logger     *slog.Logger
+		now        time.Time
+		ctx        context.Context
+	)
+
+	BeforeEach(func() {
+		spyHandler = NewHandlerSpy()
+		spyHandler.EnableSpy.Return = true
+		logger = slog.New(slogctx.NewHandler(spyHandler, slogctx.Operation("op")))
+
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx = slogctx.WithClock(context.Background(), func() time.Time {
+			return now
+		})
+	})
+
+	It("adds the path of nested operations", func() {
+		ctx, _ := slogctx.StartLogger(ctx, logger, "checkout")
+		ctx, _ = slogctx.StartLogger(ctx, logger, "charge_card")
+
+		Expect(slogctx.Operation("op").GetAttrs(ctx)).To(Equal(
+			[]slog.Attr{slog.String("op", "checkout/charge_card")},
+		))
+	})
+
+	It("logs the duration and outcome when the operation ends", func() {
+		ctx, _ := slogctx.StartLogger(ctx, logger, "checkout")
+		_, end := slogctx.StartLogger(ctx, logger, "charge_card")
+		now = now.Add(time.Second)
+		end(nil)
+
+		rec := spyHandler.HandleSpy.Rec
+		Expect(rec.Level).To(Equal(slog.LevelInfo))
+		Expect(rec.Message).To(Equal("checkout/charge_card ended"))
+		Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+			slog.Duration("duration", time.Second),
+			slog.String("outcome", "success"),
+			slog.String("op", "checkout/charge_card"),
+		}))
+
+		frame, _ := runtime.CallersFrames([]uintptr{rec.PC}).Next()
+		Expect(frame.File).To(HaveSuffix("operation_test.go"))
+	})
+
+	It("logs the error when the operation fails", func() {
+		_, end := slogctx.StartLogger(ctx, logger, "checkout")
+		err := errors.New("declined")
+		end(err)
+
+		rec := spyHandler.HandleSpy.Rec
+		Expect(rec.Level).To(Equal(slog.LevelError))
+		Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+			slog.Duration("duration", 0),
+			slog.String("outcome", "failure"),
+			slog.Any("error", err),
+			slog.String("op", "checkout"),
+		}))
+	})
+
+	When("the handler has no Operation getter", func() {
+
+		It("names the operation in the message", func() {
+			logger := slog.New(spyHandler)
+			ctx, _ := slogctx.StartLogger(ctx, logger, "checkout")
+			_, end := slogctx.StartLogger(ctx, logger, "charge_card")
+			end(nil)
+
+			rec := spyHandler.HandleSpy.Rec
+			Expect(rec.Message).To(Equal("checkout/charge_card ended"))
+			Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+				slog.Duration("duration", 0),
+				slog.String("outcome", "success"),
+			}))
+		})
+	})
+
+	It("logs only when end is first called", func() {
+		_, end := slogctx.StartLogger(ctx, logger, "checkout")
+		end(nil)
+		spyHandler.HandleSpy.Rec = slog.Record{}
+		end(errors.New("declined"))
+
+		Expect(spyHandler.HandleSpy.Rec).To(Equal(slog.Record{}))
+	})
+
+	When("the name is empty", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Start(ctx, "") }).To(PanicWith("name is empty"))
+		})
+	})
+})
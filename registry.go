@@ -0,0 +1,107 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"fmt"
+	"log"
+	"log/slog"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+var registry = struct {
+	sync.Mutex
+	getters map[string]AttrGetter
+}{
+	getters: map[string]AttrGetter{},
+}
+
+// Register makes an [AttrGetter] available by name to [Registered]. It is
+// intended to be called from a library's init function so that programs
+// can add the library's attributes without knowing about each of them.
+//
+//	func init() {
+//		slogctx.Register("grpc.method", slogctx.Attr("grpc.method", MethodFromCtx))
+//	}
+//
+// Panics if name is empty, the getter is nil, or Register is called twice
+// with the same name.
+func Register(name string, g AttrGetter) {
+	if len(name) == 0 {
+		panic("name is empty")
+	}
+	if g == nil {
+		panic("AttrGetter is nil")
+	}
+
+	registry.Lock()
+	defer registry.Unlock()
+
+	if _, dup := registry.getters[name]; dup {
+		panic(fmt.Sprintf("AttrGetter %q is already registered", name))
+	}
+	registry.getters[name] = g
+}
+
+// Registered returns the registered getters ordered by name.
+func Registered() []AttrGetter {
+	registry.Lock()
+	defer registry.Unlock()
+
+	names := make([]string, 0, len(registry.getters))
+	for name := range registry.getters {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	getters := make([]AttrGetter, len(names))
+	for i, name := range names {
+		getters[i] = registry.getters[name]
+	}
+
+	return getters
+}
+
+// Install wraps the handler of [log/slog.Default] in a [Handler] using the
+// getters, makes a logger using it the default, and returns the [Handler].
+//
+//	slogctx.Install(slogctx.Registered()...)
+//
+// If [log/slog.SetDefault] has not been called, the default handler writes
+// through the [log] package, which would then write back to the new
+// handler. In that case the target is instead a [log/slog.TextHandler]
+// writing to [log.Writer].
+//
+// Panics if receives zero [AttrGetter] instances, or any [AttrGetter]
+// references are nil.
+func Install(attrGetters ...AttrGetter) *Handler {
+	target := slog.Default().Handler()
+	if isDefaultHandler(target) {
+		target = slog.NewTextHandler(log.Writer(), nil)
+	}
+
+	h := NewHandler(target, attrGetters...)
+	slog.SetDefault(slog.New(h))
+	return h
+}
+
+// isDefaultHandler returns whether h is the unexported handler used by
+// log/slog before SetDefault is called. Wrapping it and passing the result
+// to SetDefault deadlocks, see https://go.dev/issue/61892.
+func isDefaultHandler(h slog.Handler) bool {
+	return reflect.TypeOf(h).String() == "*slog.defaultHandler"
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"strings"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Registering getters", Ordered, func() {
+
+	BeforeAll(func() {
+		slogctx.Register("registry.bar", barGetter)
+		slogctx.Register("registry.foo", fooGetter)
+	})
+
+	It("returns the registered getters ordered by name", func() {
+		Expect(slogctx.Registered()).To(Equal([]slogctx.AttrGetter{
+			barGetter,
+			fooGetter,
+		}))
+	})
+
+	When("registering the same name twice", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Register("registry.foo", pifGetter) }).
+				To(PanicWith(`AttrGetter "registry.foo" is already registered`))
+		})
+	})
+
+	When("registering an empty name", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Register("", pifGetter) }).
+				To(PanicWith("name is empty"))
+		})
+	})
+
+	When("registering a nil getter", func() {
+
+		It("panics", func() {
+			Expect(func() { slogctx.Register("registry.nil", nil) }).
+				To(PanicWith("AttrGetter is nil"))
+		})
+	})
+})
+
+var _ = Describe("Installing a handler as the default", func() {
+	var (
+		ctx     context.Context
+		logger  *slog.Logger
+		writer  = log.Writer()
+		flags   = log.Flags()
+		handler *slogctx.Handler
+	)
+
+	BeforeEach(func() {
+		ctx = context.WithValue(context.Background(), fooCtxKey, fooAttrValue)
+		logger = slog.Default()
+		DeferCleanup(func() {
+			slog.SetDefault(logger)
+			log.SetOutput(writer)
+			log.SetFlags(flags)
+		})
+	})
+
+	When("the default handler has been replaced", func() {
+		var spyHandler *HandlerSpy
+
+		BeforeEach(func() {
+			spyHandler = NewHandlerSpy()
+			spyHandler.EnableSpy.Return = true
+			slog.SetDefault(slog.New(spyHandler))
+			handler = slogctx.Install(fooGetter)
+		})
+
+		It("makes the handler the default", func() {
+			Expect(slog.Default().Handler()).To(BeIdenticalTo(handler))
+		})
+
+		It("wraps the previous default handler", func() {
+			slog.InfoContext(ctx, "test")
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(ContainElement(fooAttr))
+		})
+	})
+
+	When("the default handler has not been replaced", func() {
+		var buf *strings.Builder
+
+		BeforeEach(func() {
+			buf = &strings.Builder{}
+			log.SetOutput(buf)
+			handler = slogctx.Install(fooGetter)
+		})
+
+		It("writes to the log package's writer", func() {
+			slog.InfoContext(ctx, "test")
+			Expect(buf.String()).To(ContainSubstring(fooAttr.String()))
+		})
+
+		It("does not deadlock when using the log package", func() {
+			log.Print("test")
+			Expect(buf.String()).To(ContainSubstring("msg=test"))
+		})
+	})
+})
@@ -0,0 +1,157 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package semconv provides [slogctx.AttrGetter] instances that use the
+// attribute names defined by the OpenTelemetry semantic conventions, so
+// that every service logs the same information with the same keys.
+//
+//	h = slogctx.NewHandler(h,
+//		semconv.ServiceName("checkout"),
+//		semconv.HTTPRequestMethod(httppkg.MethodFromCtx),
+//		semconv.URLPath(httppkg.PathFromCtx),
+//		semconv.UserID(authpkg.UserIDFromCtx),
+//	)
+//
+// See https://opentelemetry.io/docs/specs/semconv/ for the definition of
+// each attribute.
+package semconv
+
+import (
+	"context"
+
+	"github.com/pfflabs/slogctx"
+)
+
+// Attribute keys defined by the OpenTelemetry semantic conventions.
+const (
+	ClientAddressKey             = "client.address"
+	DeploymentEnvironmentNameKey = "deployment.environment.name"
+	HTTPRequestMethodKey         = "http.request.method"
+	HTTPResponseStatusCodeKey    = "http.response.status_code"
+	HTTPRouteKey                 = "http.route"
+	ServerAddressKey             = "server.address"
+	ServiceInstanceIDKey         = "service.instance.id"
+	ServiceNameKey               = "service.name"
+	ServiceVersionKey            = "service.version"
+	SessionIDKey                 = "session.id"
+	URLPathKey                   = "url.path"
+	URLQueryKey                  = "url.query"
+	URLSchemeKey                 = "url.scheme"
+	UserAgentOriginalKey         = "user_agent.original"
+	UserIDKey                    = "user.id"
+	UserNameKey                  = "user.name"
+)
+
+// ClientAddress returns a getter for the client.address attribute.
+func ClientAddress(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(ClientAddressKey, lookup)
+}
+
+// HTTPRequestMethod returns a getter for the http.request.method attribute.
+func HTTPRequestMethod(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(HTTPRequestMethodKey, lookup)
+}
+
+// HTTPResponseStatusCode returns a getter for the http.response.status_code
+// attribute.
+func HTTPResponseStatusCode(lookup func(context.Context) (int, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(HTTPResponseStatusCodeKey, lookup)
+}
+
+// HTTPRoute returns a getter for the http.route attribute.
+func HTTPRoute(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(HTTPRouteKey, lookup)
+}
+
+// ServerAddress returns a getter for the server.address attribute.
+func ServerAddress(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(ServerAddressKey, lookup)
+}
+
+// SessionID returns a getter for the session.id attribute.
+func SessionID(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(SessionIDKey, lookup)
+}
+
+// URLPath returns a getter for the url.path attribute.
+func URLPath(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(URLPathKey, lookup)
+}
+
+// URLQuery returns a getter for the url.query attribute.
+func URLQuery(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(URLQueryKey, lookup)
+}
+
+// URLScheme returns a getter for the url.scheme attribute.
+func URLScheme(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(URLSchemeKey, lookup)
+}
+
+// UserAgentOriginal returns a getter for the user_agent.original attribute.
+func UserAgentOriginal(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(UserAgentOriginalKey, lookup)
+}
+
+// UserID returns a getter for the user.id attribute.
+func UserID(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(UserIDKey, lookup)
+}
+
+// UserName returns a getter for the user.name attribute.
+func UserName(lookup func(context.Context) (string, bool)) slogctx.AttrGetter {
+	return slogctx.Attr(UserNameKey, lookup)
+}
+
+// DeploymentEnvironmentName returns a getter that always returns the
+// deployment.environment.name attribute with the name.
+//
+// Panics if name is empty.
+func DeploymentEnvironmentName(name string) slogctx.AttrGetter {
+	return constant(DeploymentEnvironmentNameKey, name)
+}
+
+// ServiceInstanceID returns a getter that always returns the
+// service.instance.id attribute with the id.
+//
+// Panics if id is empty.
+func ServiceInstanceID(id string) slogctx.AttrGetter {
+	return constant(ServiceInstanceIDKey, id)
+}
+
+// ServiceName returns a getter that always returns the service.name
+// attribute with the name.
+//
+// Panics if name is empty.
+func ServiceName(name string) slogctx.AttrGetter {
+	return constant(ServiceNameKey, name)
+}
+
+// ServiceVersion returns a getter that always returns the service.version
+// attribute with the version.
+//
+// Panics if version is empty.
+func ServiceVersion(version string) slogctx.AttrGetter {
+	return constant(ServiceVersionKey, version)
+}
+
+func constant(key, value string) slogctx.AttrGetter {
+	if len(value) == 0 {
+		panic(key + " is empty")
+	}
+
+	return slogctx.Attr(key, func(context.Context) (string, bool) {
+		return value, true
+	})
+}
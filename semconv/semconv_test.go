@@ -0,0 +1,52 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package semconv_test
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/pfflabs/slogctx/semconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Getting attributes with semantic convention names", func() {
+	ctx := context.Background()
+
+	It("uses the convention's key for looked up values", func() {
+		getter := semconv.HTTPRequestMethod(func(context.Context) (string, bool) {
+			return "GET", true
+		})
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("http.request.method", "GET"),
+		}))
+	})
+
+	It("uses the convention's key for constant values", func() {
+		Expect(semconv.ServiceName("checkout").GetAttrs(ctx)).To(Equal([]slog.Attr{
+			slog.String("service.name", "checkout"),
+		}))
+	})
+
+	When("a constant value is empty", func() {
+
+		It("panics", func() {
+			Expect(func() { semconv.ServiceName("") }).
+				To(PanicWith("service.name is empty"))
+		})
+	})
+})
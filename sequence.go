@@ -0,0 +1,105 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+)
+
+type sequenceCtxKey struct{}
+
+// sequence is a counter of the records logged with a context.
+type sequence struct {
+	// prefix is the path of the sequence, e.g. "1/2" for the second child
+	// of the first child of a sequence, or empty if it has no parent.
+	prefix string
+
+	n        atomic.Uint64
+	children atomic.Uint64
+}
+
+// WithSequence returns a copy of ctx with a new sequence, which numbers the
+// records logged with the context, and contexts derived from it, from 1
+// when used with [Sequence].
+func WithSequence(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sequenceCtxKey{}, &sequence{})
+}
+
+// WithChildSequence returns a copy of ctx with a new sequence that is a
+// child of the sequence in ctx, e.g. for a goroutine handling part of a
+// request. The children of a sequence are numbered from 1 and the records
+// logged with a child are numbered with the path of the child, e.g.
+// "2:5" for the fifth record of the second child, or "2/1:5" for that of
+// the first child of the second child. The path is separated by '/' so it
+// is not mistaken for a correlation ID made by [Fork]. If ctx has no
+// sequence, it is the same as [WithSequence].
+func WithChildSequence(ctx context.Context) context.Context {
+	parent, ok := ctx.Value(sequenceCtxKey{}).(*sequence)
+	if !ok {
+		return WithSequence(ctx)
+	}
+
+	prefix := strconv.FormatUint(parent.children.Add(1), 10)
+	if len(parent.prefix) > 0 {
+		prefix = parent.prefix + "/" + prefix
+	}
+	return context.WithValue(ctx, sequenceCtxKey{}, &sequence{prefix: prefix})
+}
+
+type sequenceAttrGetter struct {
+	key string
+}
+
+// Sequence returns an [AttrGetter] that numbers the records logged with a
+// context that has a sequence, see [WithSequence], so that their order can
+// be recovered if timestamps are equal or lines are reordered. Each call
+// takes the next number, safely for concurrent use, so the getter should
+// be used by a single [Handler].
+//
+// The value is a uint64, or a string for a child sequence created by
+// [WithChildSequence].
+//
+// Panics if key is empty.
+func Sequence(key string) AttrGetter {
+	validateKey(key)
+	return &sequenceAttrGetter{key: key}
+}
+
+func (s *sequenceAttrGetter) attrKey() string {
+	return s.key
+}
+
+func (s *sequenceAttrGetter) GetAttrs(ctx context.Context) []slog.Attr {
+	return s.AppendAttrs(ctx, nil)
+}
+
+func (s *sequenceAttrGetter) AppendAttrs(
+	ctx context.Context,
+	dst []slog.Attr,
+) []slog.Attr {
+	seq, ok := ctx.Value(sequenceCtxKey{}).(*sequence)
+	if !ok {
+		return dst
+	}
+
+	n := seq.n.Add(1)
+	if len(seq.prefix) == 0 {
+		return append(dst, slog.Uint64(s.key, n))
+	}
+	return append(dst, slog.String(s.key, seq.prefix+":"+strconv.FormatUint(n, 10)))
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Numbering records", func() {
+	getter := slogctx.Sequence("seq")
+
+	It("numbers the records logged with a context from 1", func() {
+		ctx := slogctx.WithSequence(context.Background())
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{slog.Uint64("seq", 1)}))
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{slog.Uint64("seq", 2)}))
+	})
+
+	It("shares the sequence with derived contexts", func() {
+		ctx := slogctx.WithSequence(context.Background())
+		derived := context.WithValue(ctx, fooCtxKey, fooAttrValue)
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{slog.Uint64("seq", 1)}))
+		Expect(getter.GetAttrs(derived)).To(Equal([]slog.Attr{slog.Uint64("seq", 2)}))
+	})
+
+	It("takes each number once when used concurrently", func() {
+		ctx := slogctx.WithSequence(context.Background())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					getter.GetAttrs(ctx)
+				}
+			}()
+		}
+		wg.Wait()
+
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{slog.Uint64("seq", 1001)}))
+	})
+
+	It("numbers the records of child sequences with their path", func() {
+		ctx := slogctx.WithSequence(context.Background())
+		child1 := slogctx.WithChildSequence(ctx)
+		child2 := slogctx.WithChildSequence(ctx)
+		grandchild := slogctx.WithChildSequence(child2)
+
+		Expect(getter.GetAttrs(child1)).To(Equal([]slog.Attr{slog.String("seq", "1:1")}))
+		Expect(getter.GetAttrs(child2)).To(Equal([]slog.Attr{slog.String("seq", "2:1")}))
+		Expect(getter.GetAttrs(grandchild)).To(Equal([]slog.Attr{slog.String("seq", "2/1:1")}))
+		Expect(getter.GetAttrs(ctx)).To(Equal([]slog.Attr{slog.Uint64("seq", 1)}))
+	})
+
+	When("the context has no sequence", func() {
+
+		It("returns no attributes", func() {
+			Expect(getter.GetAttrs(context.Background())).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,118 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// FillTemplate returns the template with each placeholder replaced by the
+// formatted value of the attribute it names, and whether any placeholders
+// were replaced. A placeholder is a key in braces, e.g. "{user_id}", and
+// attributes in groups are named by joining the keys of the groups and the
+// attribute with a '.', e.g. "{user.id}". If more than one attribute has
+// the key the first is used. Placeholders naming no attribute are left as
+// they are.
+func FillTemplate(template string, attrs []slog.Attr) (string, bool) {
+	i := strings.IndexByte(template, '{')
+	if i < 0 {
+		return template, false
+	}
+
+	var (
+		b      strings.Builder
+		filled bool
+	)
+	b.Grow(len(template))
+
+	for ; i >= 0; i = strings.IndexByte(template, '{') {
+		b.WriteString(template[:i])
+		template = template[i:]
+
+		end := strings.IndexAny(template[1:], "{} \t\n")
+		if end < 0 || template[1+end] != '}' || end == 0 {
+			// Not a placeholder, so keep the brace and carry on after it.
+			b.WriteByte('{')
+			template = template[1:]
+			continue
+		}
+
+		key := template[1 : 1+end]
+		if v, ok := findAttr(attrs, key); ok {
+			b.WriteString(v.String())
+			filled = true
+		} else {
+			b.WriteString(template[:end+2])
+		}
+		template = template[end+2:]
+	}
+	b.WriteString(template)
+
+	return b.String(), filled
+}
+
+// findAttr returns the value of the first attribute in attrs with the
+// dotted path.
+func findAttr(attrs []slog.Attr, path string) (slog.Value, bool) {
+	for _, a := range attrs {
+		if a.Key == path {
+			return a.Value.Resolve(), true
+		}
+
+		v := a.Value.Resolve()
+		if v.Kind() != slog.KindGroup {
+			continue
+		}
+
+		rest, ok := path, true
+		if len(a.Key) > 0 {
+			rest, ok = strings.CutPrefix(path, a.Key+".")
+		}
+		if ok {
+			if gv, ok := findAttr(v.Group(), rest); ok {
+				return gv, true
+			}
+		}
+	}
+
+	return slog.Value{}, false
+}
+
+// fillMessage returns the record with the placeholders of its message
+// filled in using its attributes and the context attributes, and the
+// original message if any were filled in, see
+// [HandlerOptions.MessageTemplates].
+func (h *Handler) fillMessage(rec slog.Record, attrs []slog.Attr) (slog.Record, string) {
+	if strings.IndexByte(rec.Message, '{') < 0 {
+		return rec, ""
+	}
+
+	all := make([]slog.Attr, 0, rec.NumAttrs()+len(attrs))
+	rec.Attrs(func(a slog.Attr) bool {
+		all = append(all, a)
+		return true
+	})
+	all = append(all, attrs...)
+
+	msg, ok := FillTemplate(rec.Message, all)
+	if !ok {
+		return rec, ""
+	}
+
+	template := rec.Message
+	rec.Message = msg
+	return rec, template
+}
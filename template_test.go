@@ -0,0 +1,126 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Filling in message templates", func() {
+	attrs := []slog.Attr{
+		slog.String("user_id", "u1"),
+		slog.Group("tenant", slog.String("name", "acme")),
+	}
+
+	DescribeTable("filling in a template",
+		func(template, expected string, filled bool) {
+			msg, ok := slogctx.FillTemplate(template, attrs)
+			Expect(msg).To(Equal(expected))
+			Expect(ok).To(Equal(filled))
+		},
+		Entry("no placeholders", "hello", "hello", false),
+		Entry("a placeholder", "user {user_id} exceeded quota", "user u1 exceeded quota", true),
+		Entry("a grouped attribute", "on {tenant.name}", "on acme", true),
+		Entry("an unknown placeholder", "{user_id} {unknown}", "u1 {unknown}", true),
+		Entry("only unknown placeholders", "{unknown}", "{unknown}", false),
+		Entry("not placeholders", "{} { user_id} {user_id", "{} { user_id} {user_id", false),
+		Entry("nested braces", "{{user_id}}", "{u1}", true),
+	)
+
+	When("a handler fills in templates", func() {
+		var (
+			spyHandler *HandlerSpy
+			handler    *slogctx.Handler
+			ctx        context.Context
+		)
+
+		BeforeEach(func() {
+			ctx = context.WithValue(context.Background(), barCtxKey, barAttrValue)
+			spyHandler = NewHandlerSpy()
+			handler = slogctx.NewHandlerWithOptions(spyHandler,
+				&slogctx.HandlerOptions{MessageTemplates: true},
+				barGetter,
+			)
+		})
+
+		handle := func(msg string, attrs ...slog.Attr) slog.Record {
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, msg, 0)
+			rec.AddAttrs(attrs...)
+			Expect(handler.Handle(ctx, rec)).To(Succeed())
+			return spyHandler.HandleSpy.Rec
+		}
+
+		It("uses the record's and the context's attributes and keeps the template", func() {
+			rec := handle("{a} and {"+barAttrName+"}", slog.Int("a", 1))
+			Expect(rec.Message).To(Equal("1 and " + barAttrValue))
+			Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+				slog.Int("a", 1),
+				barAttr,
+				slog.String("msg_template", "{a} and {"+barAttrName+"}"),
+			}))
+		})
+
+		It("keeps the template at the top level when a group is opened", func() {
+			h := handler.WithGroup("g").WithAttrs([]slog.Attr{slog.Int("b", 2)})
+			rec := slog.NewRecord(time.Now(), slog.LevelInfo, "{a}", 0)
+			rec.AddAttrs(slog.Int("a", 1))
+			Expect(h.Handle(ctx, rec)).To(Succeed())
+
+			Expect(spyHandler.WithGroupSpy.Name).To(BeEmpty())
+			Expect(spyHandler.HandleSpy.Rec.Message).To(Equal("1"))
+			Expect(GetAttrs(spyHandler.HandleSpy.Rec)).To(Equal([]slog.Attr{
+				slog.Group("g", slog.Int("b", 2), slog.Int("a", 1), barAttr),
+				slog.String("msg_template", "{a}"),
+			}))
+		})
+
+		Context("and keys are restyled", func() {
+
+			BeforeEach(func() {
+				handler = slogctx.NewHandlerWithOptions(spyHandler,
+					&slogctx.HandlerOptions{
+						MessageTemplates: true,
+						KeyStyle:         slogctx.KeySnakeCase,
+					},
+					slogctx.Attr("userID", func(context.Context) (string, bool) {
+						return "u1", true
+					}),
+				)
+			})
+
+			It("fills in placeholders using the getters' keys", func() {
+				rec := handle("user {userID}")
+				Expect(rec.Message).To(Equal("user u1"))
+				Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+					slog.String("user_id", "u1"),
+					slog.String("msg_template", "user {userID}"),
+				}))
+			})
+		})
+
+		It("leaves other messages unchanged", func() {
+			rec := handle("hello {unknown}")
+			Expect(rec.Message).To(Equal("hello {unknown}"))
+			Expect(GetAttrs(rec)).To(Equal([]slog.Attr{barAttr}))
+		})
+	})
+})
@@ -0,0 +1,87 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+)
+
+// WatchOptions are options for [WatchDone]. A zero WatchOptions consists
+// entirely of default values.
+type WatchOptions struct {
+	// Level is the level of the record logged when the context is done. If
+	// nil, [log/slog.LevelWarn] is used.
+	Level slog.Leveler
+
+	// Message is the message of the record logged when the context is
+	// done. If empty, "context done" is used.
+	Message string
+}
+
+// WatchDone logs a record with the logger, once, when ctx is cancelled or
+// its deadline passes, to show why work was cut short. The record is logged
+// with ctx, so a [Handler] adds the attributes of its getters at that
+// moment, and has the attributes "err" and "cause" holding ctx's error and
+// its cause, see [context.Cause], and "lifetime" holding the time since
+// WatchDone was called. The record's source is the caller of WatchDone.
+//
+// Calling the returned stop function stops watching ctx, and returns true
+// if it did so before the record was logged, as for [context.AfterFunc].
+// It should be called when the work using ctx is complete, as ctx may never
+// be done.
+//
+// Panics if logger is nil.
+func WatchDone(
+	ctx context.Context,
+	logger *slog.Logger,
+	opts *WatchOptions,
+) (stop func() bool) {
+	if logger == nil {
+		panic("logger is nil")
+	}
+
+	var o WatchOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Level == nil {
+		o.Level = slog.LevelWarn
+	}
+	if len(o.Message) == 0 {
+		o.Message = "context done"
+	}
+
+	var pcs [1]uintptr
+	runtime.Callers(2, pcs[:])
+	start := clockNow(ctx)
+
+	return context.AfterFunc(ctx, func() {
+		level := o.Level.Level()
+		if !logger.Enabled(ctx, level) {
+			return
+		}
+
+		now := clockNow(ctx)
+		rec := slog.NewRecord(now, level, o.Message, pcs[0])
+		rec.AddAttrs(
+			slog.Any("err", ctx.Err()),
+			slog.Any("cause", context.Cause(ctx)),
+			slog.Duration("lifetime", now.Sub(start)),
+		)
+		_ = logger.Handler().Handle(ctx, rec)
+	})
+}
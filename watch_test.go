@@ -0,0 +1,121 @@
+// Copyright 2023 pfflabs
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package slogctx_test
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/pfflabs/slogctx"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// recordsHandler is a handler that keeps the records it handles, safely
+// for concurrent use.
+type recordsHandler struct {
+	mu   sync.Mutex
+	recs []slog.Record
+}
+
+func (h *recordsHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordsHandler) Handle(_ context.Context, rec slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.recs = append(h.recs, rec)
+	return nil
+}
+
+func (h *recordsHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordsHandler) WithGroup(string) slog.Handler { return h }
+
+func (h *recordsHandler) records() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]slog.Record(nil), h.recs...)
+}
+
+var _ = Describe("Watching for a context to be done", func() {
+	var (
+		target *recordsHandler
+		logger *slog.Logger
+		now    time.Time
+		ctx    context.Context
+	)
+
+	BeforeEach(func() {
+		target = &recordsHandler{}
+		logger = slog.New(slogctx.NewHandler(target, barGetter))
+
+		now = time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+		ctx = slogctx.WithClock(context.Background(), func() time.Time {
+			return now
+		})
+	})
+
+	It("logs why the context ended, and the context attributes, once", func() {
+		cause := errors.New("upstream deadline")
+		ctx, cancel := context.WithCancelCause(ctx)
+		ctx = context.WithValue(ctx, barCtxKey, barAttrValue)
+
+		slogctx.WatchDone(ctx, logger, nil)
+		cancel(cause)
+		cancel(errors.New("again"))
+
+		Eventually(target.records).Should(HaveLen(1))
+		rec := target.records()[0]
+		Expect(rec.Level).To(Equal(slog.LevelWarn))
+		Expect(rec.Message).To(Equal("context done"))
+		Expect(GetAttrs(rec)).To(Equal([]slog.Attr{
+			slog.Any("err", context.Canceled),
+			slog.Any("cause", cause),
+			slog.Duration("lifetime", 0),
+			barAttr,
+		}))
+		Consistently(target.records, "50ms").Should(HaveLen(1))
+	})
+
+	It("uses the options", func() {
+		ctx, cancel := context.WithCancel(ctx)
+		slogctx.WatchDone(ctx, logger, &slogctx.WatchOptions{
+			Level:   slog.LevelError,
+			Message: "request cut short",
+		})
+		cancel()
+
+		Eventually(target.records).Should(HaveLen(1))
+		rec := target.records()[0]
+		Expect(rec.Level).To(Equal(slog.LevelError))
+		Expect(rec.Message).To(Equal("request cut short"))
+	})
+
+	When("watching is stopped", func() {
+
+		It("logs nothing", func() {
+			ctx, cancel := context.WithCancel(ctx)
+			stop := slogctx.WatchDone(ctx, logger, nil)
+			Expect(stop()).To(BeTrue())
+			cancel()
+
+			Consistently(target.records, "50ms").Should(BeEmpty())
+		})
+	})
+})